@@ -23,7 +23,10 @@ import (
 func main() {
 	config := config.MustLoadConfig()
 
-	logger.InitGlobalLogger(os.Stdout, slog.LevelDebug)
+	if err := logger.InitGlobalLogger(os.Stdout, config.Log.Format, config.Log.Level); err != nil {
+		slog.Error("Помилка налаштування логера", "err", err.Error())
+		os.Exit(1)
+	}
 
 	app.Run(config)
 }
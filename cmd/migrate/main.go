@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"lots-service/internal/app"
+	"lots-service/internal/config"
+	"lots-service/internal/lib/logger"
+	"os"
+)
+
+// Міграції схеми БД:
+//
+//	go run ./cmd/migrate up
+//	go run ./cmd/migrate --steps=1 down
+//	go run ./cmd/migrate status
+func main() {
+	var opts app.MigrateOptions
+
+	flag.IntVar(&opts.Steps, "steps", 1, "number of migrations to roll back with down")
+
+	config := config.MustLoadConfig()
+
+	if err := logger.InitGlobalLogger(os.Stdout, config.Log.Format, config.Log.Level); err != nil {
+		slog.Error("Помилка налаштування логера", "err", err.Error())
+		os.Exit(1)
+	}
+
+	opts.Command = flag.Arg(0)
+	if opts.Command == "" {
+		opts.Command = "up"
+	}
+
+	app.RunMigrations(config, opts)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"lots-service/internal/app"
+	"lots-service/internal/config"
+	"lots-service/internal/lib/logger"
+	"os"
+	"time"
+)
+
+// Узгодження зображень сховища з лотами в БД:
+//
+//	go run ./cmd/reconcile --dry-run
+//	go run ./cmd/reconcile --grace-period=48h --interval=6h
+func main() {
+	var opts app.ReconcileOptions
+
+	flag.BoolVar(&opts.DryRun, "dry-run", false, "only report orphaned and dangling images, delete nothing")
+	flag.DurationVar(&opts.GracePeriod, "grace-period", 24*time.Hour, "minimal age of an orphaned image before it is deleted")
+	flag.DurationVar(&opts.Interval, "interval", 0, "run periodically with this interval, 0 runs once")
+
+	config := config.MustLoadConfig()
+
+	if err := logger.InitGlobalLogger(os.Stdout, config.Log.Format, config.Log.Level); err != nil {
+		slog.Error("Помилка налаштування логера", "err", err.Error())
+		os.Exit(1)
+	}
+
+	app.RunImagesReconcile(config, opts)
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"lots-service/internal/config"
+	"lots-service/internal/domain"
+	"lots-service/internal/repository"
+	"lots-service/internal/service"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+type ReconcileOptions struct {
+	GracePeriod time.Duration
+	Interval    time.Duration
+	DryRun      bool
+}
+
+// RunImagesReconcile виконує узгодження один раз, а з ненульовим Interval
+// працює як воркер до отримання SIGINT/SIGTERM.
+func RunImagesReconcile(cfg *config.Config, opts ReconcileOptions) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing := setupTracing(ctx, cfg)
+	defer shutdownTracing()
+
+	db, err := openDB(ctx, cfg)
+	if err != nil {
+		slog.Error("Помилка підключення до БД", "err", err.Error())
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	repo := repository.NewPostgresLotsRepo(db, cfg.DB.QueryTimeout)
+	lotsService := service.NewLotsService(repo, cfg.StorageURL, newImageURLBuilder(cfg), newUploadSigner(cfg))
+
+	for {
+		report, err := lotsService.ReconcileImages(ctx, opts.GracePeriod, opts.DryRun)
+		if err != nil {
+			slog.Error("Помилка узгодження зображень", "err", err.Error())
+		}
+		if report != nil {
+			logReconcileReport(report)
+		}
+
+		if opts.Interval <= 0 {
+			if err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+func logReconcileReport(report *domain.ImagesReconcileReport) {
+	slog.Info("Узгодження зображень завершено",
+		"dryRun", report.DryRun,
+		"orphans", len(report.Orphans),
+		"deleted", len(report.Deleted),
+		"skipped", len(report.Skipped),
+		"danglingLots", len(report.Dangling),
+	)
+
+	if report.DryRun && len(report.Deleted) > 0 {
+		slog.Info("Зображення до видалення", "images", report.Deleted)
+	}
+
+	for lotID, images := range report.Dangling {
+		slog.Warn("Лот посилається на відсутні зображення", "lotID", lotID, "images", images)
+	}
+}
@@ -1,22 +1,195 @@
 package app
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
 	"lots-service/internal/config"
 	"lots-service/internal/delivery/http_handlers"
+	"lots-service/internal/lib/imageurl"
+	"lots-service/internal/lib/metrics"
+	"lots-service/internal/lib/tracing"
 	"lots-service/internal/repository"
 	"lots-service/internal/server"
 	"lots-service/internal/service"
+	"lots-service/pkg/auth"
 	"lots-service/pkg/database"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func Run(cfg *config.Config) {
-	db := database.NewPostgresConnection(cfg.DB.Host, cfg.DB.DBName, cfg.DB.User, cfg.DB.Password)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	repo := repository.NewPostgresLotsRepo(db)
-	lotsService := service.NewLotsService(repo, cfg.StorageURL)
+	shutdownTracing := setupTracing(ctx, cfg)
+	defer shutdownTracing()
+
+	db, err := openDB(ctx, cfg)
+	if err != nil {
+		slog.Error("Помилка підключення до БД", "err", err.Error())
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	go database.ReportPoolStats(ctx, db, cfg.DB.StatsInterval)
+
+	if err := metrics.RegisterDB(db, cfg.DB.DBName); err != nil {
+		slog.Warn("Не вдалося зареєструвати метрики пулу БД", "err", err.Error())
+	}
+
+	if cfg.AutoMigrate {
+		if err := autoMigrate(ctx, db); err != nil {
+			slog.Error("Помилка застосування міграцій", "err", err.Error())
+			os.Exit(1)
+		}
+	}
+
+	revocations, err := newRevocationStore(cfg, db)
+	if err != nil {
+		slog.Error("Помилка налаштування відкликання токенів", "err", err.Error())
+		os.Exit(1)
+	}
+
+	authenticator, err := auth.NewAuthenticator(auth.Options{
+		Algorithm:           cfg.Auth.Algorithm,
+		Secret:              []byte(cfg.Auth.Secret),
+		PublicKeyPath:       cfg.Auth.PublicKeyPath,
+		JWKSURL:             cfg.Auth.JWKSURL,
+		KeysRefreshInterval: cfg.Auth.KeysRefreshInterval,
+		Issuer:              cfg.Auth.Issuer,
+		Audience:            cfg.Auth.Audience,
+		ClockSkew:           cfg.Auth.ClockSkew,
+		Revocations:         revocations,
+		ErrorWriter:         server.WriteAuthError,
+	})
+	if err != nil {
+		slog.Error("Помилка налаштування авторизації", "err", err.Error())
+		os.Exit(1)
+	}
+	defer authenticator.Close()
+
+	serviceKeys := make([]auth.ServiceKey, 0, len(cfg.Internal.Keys))
+	for _, key := range cfg.Internal.Keys {
+		if key.Key == "" {
+			slog.Warn("Внутрішній API-ключ не задано, сервіс не матиме доступу", "service", key.Name, "env", key.KeyEnv)
+			continue
+		}
+		serviceKeys = append(serviceKeys, auth.ServiceKey{Name: key.Name, Key: key.Key, Scopes: key.Scopes})
+	}
+
+	apiKeys, err := auth.NewAPIKeyAuthenticator(serviceKeys, server.WriteAuthError)
+	if err != nil {
+		slog.Error("Помилка налаштування внутрішніх API-ключів", "err", err.Error())
+		os.Exit(1)
+	}
+
+	repo := repository.NewPostgresLotsRepo(db, cfg.DB.QueryTimeout)
+	lotsService := service.NewLotsService(repo, cfg.StorageURL, newImageURLBuilder(cfg), newUploadSigner(cfg))
 	lotsHandler := http_handlers.NewLotsHandler(lotsService)
 
-	handler := server.NewRouter(lotsHandler)
+	health := server.NewHealth(cfg.Health.CheckTimeout, newHealthChecks(cfg, db, lotsService)...)
+
+	handler := server.NewRouter(lotsHandler, authenticator, apiKeys, revocations, health, server.BodyLimits{
+		MaxBodyBytes:   int64(cfg.HTTP.MaxBodyBytes),
+		MaxUploadBytes: int64(cfg.HTTP.MaxUploadBytes),
+	})
+
+	server.StartServer(handler, server.ServerOptions{
+		Port:              cfg.Port,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		MaxHeaderBytes:    cfg.HTTP.MaxHeaderBytes,
+		ShutdownTimeout:   cfg.Timeout,
+		DrainDelay:        cfg.Health.DrainDelay,
+	}, health)
+}
+
+func newHealthChecks(cfg *config.Config, db *sql.DB, lotsService *service.LotsService) []server.HealthCheck {
+	checks := []server.HealthCheck{{Name: "db", Check: db.PingContext}}
+	if cfg.Health.CheckStorage {
+		checks = append(checks, server.HealthCheck{Name: "storage", Check: lotsService.PingStorage})
+	}
+
+	return checks
+}
+
+// setupTracing налаштовує експорт спанів. Помилка експортера не зупиняє
+// сервіс: трасування вимикається, а запити обслуговуються далі.
+func setupTracing(ctx context.Context, cfg *config.Config) func() {
+	shutdown, err := tracing.Setup(ctx, tracing.Options{
+		ServiceName: cfg.Tracing.ServiceName,
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		slog.Warn("Не вдалося налаштувати трасування", "err", err.Error())
+		return func() {}
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := shutdown(ctx); err != nil {
+			slog.Warn("Помилка відправки спанів при завершенні", "err", err.Error())
+		}
+	}
+}
+
+func openDB(ctx context.Context, cfg *config.Config) (*sql.DB, error) {
+	return database.NewPostgresConnection(ctx, database.Options{
+		DSN:             cfg.DB.DSN,
+		Host:            cfg.DB.Host,
+		Port:            cfg.DB.Port,
+		DBName:          cfg.DB.DBName,
+		User:            cfg.DB.User,
+		Password:        cfg.DB.Password,
+		SSLMode:         cfg.DB.SSLMode,
+		MaxOpenConns:    cfg.DB.MaxOpenConns,
+		MaxIdleConns:    cfg.DB.MaxIdleConns,
+		ConnMaxLifetime: cfg.DB.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.DB.ConnMaxIdleTime,
+		Retry: database.RetryPolicy{
+			Attempts:       cfg.DB.ConnectAttempts,
+			InitialBackoff: cfg.DB.ConnectBackoff,
+			MaxBackoff:     cfg.DB.ConnectMaxBackoff,
+		},
+	})
+}
+
+func newImageURLBuilder(cfg *config.Config) *imageurl.Builder {
+	return imageurl.NewBuilder(
+		cfg.Images.PublicURL,
+		[]byte(cfg.Images.SigningSecret),
+		cfg.Images.SignedURLTTL,
+	)
+}
+
+func newUploadSigner(cfg *config.Config) *imageurl.UploadSigner {
+	return imageurl.NewUploadSigner(
+		cfg.StorageURL,
+		[]byte(cfg.Images.SigningSecret),
+		cfg.Images.UploadTTL,
+	)
+}
 
-	server.StartServer(handler, cfg.Port, cfg.Timeout)
+func newRevocationStore(cfg *config.Config, db *sql.DB) (auth.RevocationStore, error) {
+	switch cfg.Auth.Revocation {
+	case "", "none":
+		return nil, nil
+	case "memory":
+		return auth.NewMemoryRevocations(cfg.Auth.RevocationUserTTL), nil
+	case "postgres":
+		return repository.NewPostgresRevocations(db, cfg.DB.QueryTimeout), nil
+	default:
+		return nil, fmt.Errorf("unknown revocation store: %q", cfg.Auth.Revocation)
+	}
 }
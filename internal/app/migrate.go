@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"lots-service/internal/config"
+	"lots-service/internal/migrations"
+	"lots-service/pkg/database"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+type MigrateOptions struct {
+	// up, down або status
+	Command string
+	// Кількість міграцій для відкату командою down
+	Steps int
+}
+
+// RunMigrations виконує команду міграцій схеми БД
+func RunMigrations(cfg *config.Config, opts MigrateOptions) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := openDB(ctx, cfg)
+	if err != nil {
+		slog.Error("Помилка підключення до БД", "err", err.Error())
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		slog.Error("Помилка читання міграцій", "err", err.Error())
+		os.Exit(1)
+	}
+
+	switch opts.Command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			slog.Error("Помилка застосування міграцій", "err", err.Error())
+			os.Exit(1)
+		}
+		slog.Info("Міграції застосовано", "applied", applied)
+
+	case "down":
+		reverted, err := migrator.Down(ctx, opts.Steps)
+		if err != nil {
+			slog.Error("Помилка відкату міграцій", "err", err.Error())
+			os.Exit(1)
+		}
+		slog.Info("Міграції відкочено", "reverted", reverted)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			slog.Error("Помилка отримання статусу міграцій", "err", err.Error())
+			os.Exit(1)
+		}
+		for _, status := range statuses {
+			applied := "не застосована"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+
+	default:
+		slog.Error("Невідома команда міграцій, очікується up, down або status", "command", opts.Command)
+		os.Exit(2)
+	}
+}
+
+// autoMigrate застосовує міграції під час старту сервісу (auto_migrate у конфігу)
+func autoMigrate(ctx context.Context, db *sql.DB) error {
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Автоматичні міграції виконано", "applied", applied)
+	return nil
+}
@@ -1,7 +1,12 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"time"
 
@@ -9,89 +14,245 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+const defaultConfigPath = "./configs/config.yaml"
+
+// Конфіг збирається шарами: значення за замовчуванням, YAML-файл, змінні оточення
+// (тег env; необов'язковий .env підвантажується в оточення). Поля з тегом
+// secret:"true" приховуються в --print-config.
+
 type Config struct {
-	DB         DBConfig
-	Port       string        `yaml:"port"`
-	Timeout    time.Duration `yaml:"timeout"`
-	StorageURL string        `yaml:"storage_service_url"`
+	DB      DBConfig      `yaml:"db"`
+	Port    string        `yaml:"port" env:"PORT"`
+	Timeout time.Duration `yaml:"timeout" env:"TIMEOUT"`
+	// Застосовувати міграції схеми БД під час старту сервісу
+	AutoMigrate bool          `yaml:"auto_migrate" env:"AUTO_MIGRATE"`
+	StorageURL  string        `yaml:"storage_service_url" env:"STORAGE_SERVICE_URL"`
+	Images      ImagesConfig  `yaml:"images"`
+	Auth        AuthConfig    `yaml:"auth"`
+	Internal    InternalAPI   `yaml:"internal_api"`
+	Health      HealthConfig  `yaml:"health"`
+	Tracing     TracingConfig `yaml:"tracing"`
+	Log         LogConfig     `yaml:"log"`
+	HTTP        HTTPConfig    `yaml:"http"`
 }
 
-type DBConfig struct {
-	Host     string
-	DBName   string
-	User     string
-	Password string
+// HTTPConfig обмежує повільних і надто великих клієнтів (slowloris, великі тіла)
+type HTTPConfig struct {
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES"`
+	// Ліміт тіла звичайних запитів (JSON, urlencoded форми)
+	MaxBodyBytes int `yaml:"max_body_bytes" env:"HTTP_MAX_BODY_BYTES"`
+	// Ліміт тіла multipart-запитів із зображеннями
+	MaxUploadBytes int `yaml:"max_upload_bytes" env:"HTTP_MAX_UPLOAD_BYTES"`
 }
 
-func MustLoadConfig() *Config {
-	path := fetchConfigPath()
-
-	if path == "" {
-		panic("Config path is empty")
-	}
+type LogConfig struct {
+	// pretty — кольоровий вивід для розробки, json — один рядок на запис
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+	// debug, info, warn або error; змінюється під час роботи через /api/admin/log_level
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+}
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		panic("Config file does not exist: " + path)
-	}
+type TracingConfig struct {
+	// none, stdout або otlp
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// host:port колектора OTLP/HTTP; порожній — береться з OTEL_EXPORTER_OTLP_ENDPOINT
+	Endpoint string `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	Insecure bool   `yaml:"insecure" env:"TRACING_INSECURE"`
+	// Частка запитів, що трасуються, від 0 до 1
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+	ServiceName string  `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+}
 
-	var cfg Config
-	data, _ := os.ReadFile(path)
+type HealthConfig struct {
+	// Таймаут перевірок залежностей у /readyz
+	CheckTimeout time.Duration `yaml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT"`
+	// Чи перевіряти доступність storage у /readyz
+	CheckStorage bool `yaml:"check_storage" env:"HEALTH_CHECK_STORAGE"`
+	// Скільки чекати після виключення готовності перед зупинкою сервера
+	DrainDelay time.Duration `yaml:"drain_delay" env:"HEALTH_DRAIN_DELAY"`
+}
 
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		panic("Failed to unmarshal yaml")
-	}
+type InternalAPI struct {
+	Keys []ServiceKeyConfig `yaml:"keys"`
+}
 
-	dbConfig := getDBconfig()
+// ServiceKeyConfig описує ключ внутрішнього сервісу. Сам ключ не зберігається
+// в YAML, а читається зі змінної оточення KeyEnv.
+type ServiceKeyConfig struct {
+	Name   string   `yaml:"name"`
+	KeyEnv string   `yaml:"key_env"`
+	Scopes []string `yaml:"scopes"`
+	Key    string   `yaml:"-" secret:"true"`
+}
 
-	cfg.DB = dbConfig
+type AuthConfig struct {
+	Algorithm           string        `yaml:"algorithm" env:"AUTH_ALGORITHM"`
+	PublicKeyPath       string        `yaml:"public_key_path" env:"AUTH_PUBLIC_KEY_PATH"`
+	JWKSURL             string        `yaml:"jwks_url" env:"AUTH_JWKS_URL"`
+	KeysRefreshInterval time.Duration `yaml:"keys_refresh_interval" env:"AUTH_KEYS_REFRESH_INTERVAL"`
+	Issuer              string        `yaml:"issuer" env:"AUTH_ISSUER"`
+	Audience            string        `yaml:"audience" env:"AUTH_AUDIENCE"`
+	ClockSkew           time.Duration `yaml:"clock_skew" env:"AUTH_CLOCK_SKEW"`
+	// none, memory або postgres
+	Revocation        string        `yaml:"revocation" env:"AUTH_REVOCATION"`
+	RevocationUserTTL time.Duration `yaml:"revocation_user_ttl" env:"AUTH_REVOCATION_USER_TTL"`
+	Secret            string        `yaml:"-" env:"JWT_SECRET" secret:"true"`
+}
 
-	return &cfg
+type ImagesConfig struct {
+	// Базова адреса CDN, за замовчуванням зображення віддає storage
+	PublicURL string `yaml:"public_url" env:"IMAGES_PUBLIC_URL"`
+	// Час дії підписаних посилань на зображення прихованих лотів
+	SignedURLTTL  time.Duration `yaml:"signed_url_ttl" env:"IMAGES_SIGNED_URL_TTL"`
+	UploadTTL     time.Duration `yaml:"upload_session_ttl" env:"IMAGES_UPLOAD_SESSION_TTL"`
+	SigningSecret string        `yaml:"-" env:"STORAGE_SIGNING_SECRET" secret:"true"`
 }
 
-func fetchConfigPath() string {
-	var result string
+// Паролі та DSN задаються лише через оточення, щоб не потрапляти в YAML
+type DBConfig struct {
+	DSN      string `yaml:"-" env:"DB_DSN" secret:"true"`
+	Host     string `yaml:"host" env:"DB_HOST"`
+	Port     string `yaml:"port" env:"DB_PORT"`
+	DBName   string `yaml:"name" env:"DB_NAME"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"-" env:"DB_PASSWORD" secret:"true"`
+	SSLMode  string `yaml:"sslmode" env:"DB_SSLMODE"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+
+	// Очікування БД під час старту: кількість спроб і експоненційна затримка
+	ConnectAttempts   int           `yaml:"connect_attempts" env:"DB_CONNECT_ATTEMPTS"`
+	ConnectBackoff    time.Duration `yaml:"connect_backoff" env:"DB_CONNECT_BACKOFF"`
+	ConnectMaxBackoff time.Duration `yaml:"connect_max_backoff" env:"DB_CONNECT_MAX_BACKOFF"`
 
+	// Як часто писати стан пулу в лог, 0 — не писати
+	StatsInterval time.Duration `yaml:"stats_interval" env:"DB_STATS_INTERVAL"`
+	// Максимальна тривалість одного запиту до БД, 0 — без обмеження
+	QueryTimeout time.Duration `yaml:"query_timeout" env:"DB_QUERY_TIMEOUT"`
+}
+
+// MustLoadConfig читає прапорці --config і --print-config та завантажує конфіг.
+// Помилки конфігу виводяться всі разом, після чого процес завершується.
+func MustLoadConfig() *Config {
 	// --config="path/to/config.yaml"
-	flag.StringVar(&result, "config", "./configs/config.yaml", "path to config file")
+	path := flag.String("config", "", "path to config file (default $CONFIG_PATH or "+defaultConfigPath+")")
+	printConfig := flag.Bool("print-config", false, "print the resolved config with secrets redacted and exit")
 	flag.Parse()
 
-	if result == "" {
-		result = os.Getenv("CONFIG_PATH")
+	cfg, err := Load(configPath(*path))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 
-	return result
+	if *printConfig {
+		if err := Print(os.Stdout, cfg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	return cfg
 }
 
-func getDBconfig() DBConfig {
-	err := godotenv.Load()
-	if err != nil {
-		panic("Failed to load .env file")
+func configPath(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if path := os.Getenv("CONFIG_PATH"); path != "" {
+		return path
 	}
 
-	host := os.Getenv("DB_HOST")
-	if host == "" {
-		panic("DB_HOST is not set")
+	return defaultConfigPath
+}
+
+// Load збирає і перевіряє конфіг. Порожній path означає конфіг без YAML-файлу.
+func Load(path string) (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("load .env: %w", err)
+	}
+
+	cfg := defaults()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config: %w", err)
+		}
+
+		// Невідомі ключі — помилка, щоб опечатка не вимикала налаштування мовчки
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("parse config %s: %w", path, err)
+		}
 	}
 
-	dbName := os.Getenv("DB_NAME")
-	if dbName == "" {
-		panic("DB_NAME is not set")
+	// Помилки оточення виводяться разом із помилками перевірки
+	envErr := applyEnv(cfg)
+
+	for i := range cfg.Internal.Keys {
+		cfg.Internal.Keys[i].Key = os.Getenv(cfg.Internal.Keys[i].KeyEnv)
 	}
 
-	user := os.Getenv("DB_USER")
-	if user == "" {
-		panic("DB_USER is not set")
+	if cfg.Images.PublicURL == "" {
+		cfg.Images.PublicURL = cfg.StorageURL + "/api/storage/images"
 	}
 
-	password := os.Getenv("DB_PASSWORD")
-	if password == "" {
-		panic("DB_PASSWORD is not set")
+	if err := errors.Join(envErr, cfg.Validate()); err != nil {
+		return nil, err
 	}
 
-	return DBConfig{
-		Host:     host,
-		DBName:   dbName,
-		User:     user,
-		Password: password,
+	return cfg, nil
+}
+
+func defaults() *Config {
+	return &Config{
+		Port:    "3011",
+		Timeout: 5 * time.Second,
+		DB: DBConfig{
+			SSLMode:           "disable",
+			ConnectAttempts:   6,
+			ConnectBackoff:    time.Second,
+			ConnectMaxBackoff: 30 * time.Second,
+		},
+		Images: ImagesConfig{
+			SignedURLTTL: 15 * time.Minute,
+			UploadTTL:    30 * time.Minute,
+		},
+		Health: HealthConfig{
+			CheckTimeout: 2 * time.Second,
+		},
+		HTTP: HTTPConfig{
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       60 * time.Second,
+			WriteTimeout:      90 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+			MaxBodyBytes:      1 << 20,
+			MaxUploadBytes:    100 << 20,
+		},
+		Log: LogConfig{
+			Format: "pretty",
+			Level:  "debug",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			SampleRatio: 1,
+			ServiceName: "lots-service",
+		},
+		Auth: AuthConfig{
+			Algorithm:           "HS256",
+			KeysRefreshInterval: 10 * time.Minute,
+			RevocationUserTTL:   24 * time.Hour,
+		},
 	}
 }
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	return path
+}
+
+// setRequiredEnv задає секрети, без яких конфіг не проходить перевірку
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("DB_DSN", "postgres://lots@localhost/lots")
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("STORAGE_SERVICE_URL", "http://storage:3013")
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	setRequiredEnv(t)
+	path := writeConfig(t, `
+port: 4000
+timeout: 7s
+db:
+  max_open_conns: 10
+`)
+	t.Setenv("PORT", "5000")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.Port != "5000" {
+		t.Fatalf("port = %q, want env value 5000", cfg.Port)
+	}
+	if cfg.Timeout != 7*time.Second || cfg.DB.MaxOpenConns != 10 {
+		t.Fatalf("timeout = %v, max_open_conns = %d, want file values 7s and 10", cfg.Timeout, cfg.DB.MaxOpenConns)
+	}
+	if cfg.DB.ConnectAttempts != 6 || cfg.Log.Format != "pretty" {
+		t.Fatalf("connect_attempts = %d, log.format = %q, want defaults", cfg.DB.ConnectAttempts, cfg.Log.Format)
+	}
+}
+
+func TestLoadReportsAllErrors(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("PORT", "abc")
+	t.Setenv("DB_MAX_OPEN_CONNS", "many")
+	t.Setenv("TIMEOUT", "soon")
+
+	_, err := Load("")
+	if err == nil {
+		t.Fatalf("Load succeeded with invalid env")
+	}
+	if !errors.Is(err, errInvalidConfig) {
+		t.Fatalf("err = %v, want validation errors too", err)
+	}
+
+	for _, want := range []string{"DB_MAX_OPEN_CONNS", "TIMEOUT", "port: must be a number"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("err does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	setRequiredEnv(t)
+	path := writeConfig(t, `
+port: 4000
+db:
+  max_open_cons: 10
+`)
+
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "max_open_cons") {
+		t.Fatalf("err = %v, want unknown key max_open_cons", err)
+	}
+}
+
+// Конфіги з репозиторію не мають ключів, яких не знає Config
+func TestShippedConfigsParse(t *testing.T) {
+	setRequiredEnv(t)
+
+	for _, name := range []string{"config.yaml", "container.config.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(filepath.Join("..", "..", "configs", name))
+			if err != nil && !errors.Is(err, errInvalidConfig) {
+				t.Fatalf("Load: %v", err)
+			}
+		})
+	}
+}
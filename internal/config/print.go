@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+// Print виводить підсумковий конфіг у YAML. Секрети замінюються на [REDACTED],
+// а поля, які задаються лише через оточення, показуються під іменем змінної.
+func Print(w io.Writer, cfg *Config) error {
+	node := printable(reflect.ValueOf(*cfg))
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return fmt.Errorf("print config: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+func printable(v reflect.Value) *yaml.Node {
+	switch {
+	case v.Type() == durationType:
+		return scalar(time.Duration(v.Int()).String())
+	case v.Kind() == reflect.Struct:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			value := printable(v.Field(i))
+			if field.Tag.Get("secret") == "true" && !v.Field(i).IsZero() {
+				value = scalar(redacted)
+			}
+
+			node.Content = append(node.Content, scalar(fieldName(field)), value)
+		}
+		return node
+	case v.Kind() == reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		if v.Len() > 0 && v.Index(0).Kind() == reflect.Struct {
+			node.Style = 0
+		}
+		for i := 0; i < v.Len(); i++ {
+			node.Content = append(node.Content, printable(v.Index(i)))
+		}
+		return node
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v.Interface()); err != nil {
+			return scalar(fmt.Sprint(v.Interface()))
+		}
+		return node
+	}
+}
+
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name != "" && name != "-" {
+		return name
+	}
+	if env := field.Tag.Get("env"); env != "" {
+		return "$" + env
+	}
+
+	return strings.ToLower(field.Name)
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"lots-service/internal/lib/logger"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var errInvalidConfig = errors.New("invalid config")
+
+var (
+	sslModes         = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	jwtAlgorithms    = []string{"HS256", "RS256"}
+	revocationStores = []string{"", "none", "memory", "postgres"}
+	tracingExporters = []string{"none", "stdout", "otlp"}
+	logFormats       = []string{logger.FormatPretty, logger.FormatJSON}
+)
+
+const (
+	requiredForNoDSN  = "is required when DB_DSN is not set"
+	mustNotBeNegative = "must not be negative"
+)
+
+// Validate перевіряє всі поля і повертає всі знайдені помилки разом
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(field, format string, args ...any) {
+		problems = append(problems, field+": "+fmt.Sprintf(format, args...))
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		add("port", "must be a number between 1 and 65535, got %q", c.Port)
+	}
+	if c.Timeout <= 0 {
+		add("timeout", "must be positive")
+	}
+	if !isAbsoluteURL(c.StorageURL) {
+		add("storage_service_url", "must be an absolute URL, got %q", c.StorageURL)
+	}
+
+	c.DB.validate(add)
+	c.Auth.validate(add)
+	c.Images.validate(add)
+	c.HTTP.validate(add)
+
+	if c.Health.CheckTimeout <= 0 {
+		add("health.check_timeout", "must be positive")
+	}
+	if c.Health.DrainDelay < 0 {
+		add("health.drain_delay", mustNotBeNegative)
+	}
+
+	if !slices.Contains(tracingExporters, c.Tracing.Exporter) {
+		add("tracing.exporter", "must be one of %v, got %q", tracingExporters, c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+	if c.Tracing.ServiceName == "" {
+		add("tracing.service_name", "is required")
+	}
+
+	if !slices.Contains(logFormats, c.Log.Format) {
+		add("log.format", "must be one of %v, got %q", logFormats, c.Log.Format)
+	}
+	if _, err := logger.ParseLevel(c.Log.Level); err != nil {
+		add("log.level", "must be one of debug, info, warn, error, got %q", c.Log.Level)
+	}
+
+	for i, key := range c.Internal.Keys {
+		if key.Name == "" {
+			add(fmt.Sprintf("internal_api.keys[%d].name", i), "is required")
+		}
+		if key.KeyEnv == "" {
+			add(fmt.Sprintf("internal_api.keys[%d].key_env", i), "is required")
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w:\n  - %s", errInvalidConfig, strings.Join(problems, "\n  - "))
+}
+
+func (db *DBConfig) validate(add func(field, format string, args ...any)) {
+	if db.DSN == "" {
+		if db.Host == "" {
+			add("db.host (DB_HOST)", requiredForNoDSN)
+		}
+		if db.DBName == "" {
+			add("db.name (DB_NAME)", requiredForNoDSN)
+		}
+		if db.User == "" {
+			add("db.user (DB_USER)", requiredForNoDSN)
+		}
+		if db.Password == "" {
+			add("DB_PASSWORD", requiredForNoDSN)
+		}
+	}
+
+	if !slices.Contains(sslModes, db.SSLMode) {
+		add("db.sslmode", "must be one of %v, got %q", sslModes, db.SSLMode)
+	}
+	if db.MaxOpenConns < 0 {
+		add("db.max_open_conns", mustNotBeNegative)
+	}
+	if db.MaxIdleConns < 0 {
+		add("db.max_idle_conns", mustNotBeNegative)
+	}
+	if db.MaxOpenConns > 0 && db.MaxIdleConns > db.MaxOpenConns {
+		add("db.max_idle_conns", "must not exceed max_open_conns (%d)", db.MaxOpenConns)
+	}
+	if db.ConnMaxLifetime < 0 {
+		add("db.conn_max_lifetime", mustNotBeNegative)
+	}
+	if db.ConnMaxIdleTime < 0 {
+		add("db.conn_max_idle_time", mustNotBeNegative)
+	}
+	if db.ConnectAttempts < 1 {
+		add("db.connect_attempts", "must be at least 1")
+	}
+	if db.ConnectBackoff < 0 {
+		add("db.connect_backoff", mustNotBeNegative)
+	}
+	if db.ConnectMaxBackoff < 0 {
+		add("db.connect_max_backoff", mustNotBeNegative)
+	}
+	if db.StatsInterval < 0 {
+		add("db.stats_interval", mustNotBeNegative)
+	}
+	if db.QueryTimeout < 0 {
+		add("db.query_timeout", mustNotBeNegative)
+	}
+}
+
+func (a *AuthConfig) validate(add func(field, format string, args ...any)) {
+	switch a.Algorithm {
+	case "HS256":
+		if a.Secret == "" {
+			add("JWT_SECRET", "is required for %s", a.Algorithm)
+		}
+	case "RS256":
+		if a.PublicKeyPath == "" && a.JWKSURL == "" {
+			add("auth.public_key_path / auth.jwks_url", "one of them is required for %s", a.Algorithm)
+		}
+	default:
+		add("auth.algorithm", "must be one of %v, got %q", jwtAlgorithms, a.Algorithm)
+	}
+
+	if a.JWKSURL != "" && !isAbsoluteURL(a.JWKSURL) {
+		add("auth.jwks_url", "must be an absolute URL, got %q", a.JWKSURL)
+	}
+	if a.KeysRefreshInterval <= 0 {
+		add("auth.keys_refresh_interval", "must be positive")
+	}
+	if a.ClockSkew < 0 {
+		add("auth.clock_skew", mustNotBeNegative)
+	}
+	if !slices.Contains(revocationStores, a.Revocation) {
+		add("auth.revocation", "must be one of none, memory, postgres, got %q", a.Revocation)
+	}
+	if a.RevocationUserTTL <= 0 {
+		add("auth.revocation_user_ttl", "must be positive")
+	}
+}
+
+func (i *ImagesConfig) validate(add func(field, format string, args ...any)) {
+	if !isAbsoluteURL(i.PublicURL) {
+		add("images.public_url", "must be an absolute URL, got %q", i.PublicURL)
+	}
+	if i.SignedURLTTL <= 0 {
+		add("images.signed_url_ttl", "must be positive")
+	}
+	if i.UploadTTL <= 0 {
+		add("images.upload_session_ttl", "must be positive")
+	}
+}
+
+func (h *HTTPConfig) validate(add func(field, format string, args ...any)) {
+	if h.ReadHeaderTimeout <= 0 {
+		add("http.read_header_timeout", "must be positive")
+	}
+	if h.ReadTimeout < 0 {
+		add("http.read_timeout", mustNotBeNegative)
+	}
+	if h.WriteTimeout < 0 {
+		add("http.write_timeout", mustNotBeNegative)
+	}
+	if h.IdleTimeout < 0 {
+		add("http.idle_timeout", mustNotBeNegative)
+	}
+	if h.MaxHeaderBytes <= 0 {
+		add("http.max_header_bytes", "must be positive")
+	}
+	if h.MaxBodyBytes <= 0 {
+		add("http.max_body_bytes", "must be positive")
+	}
+	if h.MaxUploadBytes < h.MaxBodyBytes {
+		add("http.max_upload_bytes", "must not be less than max_body_bytes (%d)", h.MaxBodyBytes)
+	}
+}
+
+func isAbsoluteURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
@@ -0,0 +1,112 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"lots-service/internal/domain"
+	"lots-service/internal/lib/i18n"
+	"lots-service/internal/lib/logger"
+	"lots-service/internal/lib/responseHTTP"
+	"lots-service/pkg/auth"
+	"net/http"
+)
+
+type lotVisibilityRequest struct {
+	Hidden bool   `json:"hidden"`
+	Reason string `json:"reason"`
+}
+
+func (h *LotsHandler) AdminDeleteLot(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
+		return
+	}
+
+	lotID, err := ParseLotID(r)
+	if err != nil {
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	err = h.service.AdminDeleteLot(r.Context(), principal.UserID, lotID, r.URL.Query().Get("reason"))
+	if err != nil {
+		logger.FromContext(r.Context()).Debug("Помилка видалення лота адміністратором", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	responseHTTP.JSONRespMessage(w, r, http.StatusOK, i18n.MsgLotDeleted)
+}
+
+func (h *LotsHandler) AdminSetLotVisibility(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
+		return
+	}
+
+	lotID, err := ParseLotID(r)
+	if err != nil {
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	var req lotVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responseHTTP.WriteError(w, r, domain.NewValidation(domain.CodeInvalidRequest, nil).Wrap(err))
+		return
+	}
+
+	err = h.service.AdminSetLotHidden(r.Context(), principal.UserID, lotID, req.Hidden, req.Reason)
+	if err != nil {
+		logger.FromContext(r.Context()).Debug("Помилка зміни видимості лота", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	if req.Hidden {
+		responseHTTP.JSONRespMessage(w, r, http.StatusOK, i18n.MsgLotHidden)
+		return
+	}
+
+	responseHTTP.JSONRespMessage(w, r, http.StatusOK, i18n.MsgLotShown)
+}
+
+func (h *LotsHandler) AdminUpdateLot(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
+		return
+	}
+
+	lotID, err := ParseLotID(r)
+	if err != nil {
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	files, err := ParseLotForm(r)
+	if err != nil {
+		logger.FromContext(r.Context()).Debug("Помилка парсингу форми", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	lot, err := ParseLotFromRequest(r)
+	if err != nil {
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+	lot.LotID = lotID
+
+	err = h.service.AdminUpdateLot(r.Context(), principal.UserID, &lot,
+		files, ParseUploadedImages(r),
+		r.Form["DeleteImagesNames"], r.Form["OldImagesNames"], r.FormValue("Reason"))
+	if err != nil {
+		logger.FromContext(r.Context()).Debug("Помилка оновлення лота адміністратором", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	responseHTTP.JSONRespMessage(w, r, http.StatusOK, i18n.MsgLotUpdated)
+}
@@ -0,0 +1,49 @@
+package http_handlers
+
+import (
+	"lots-service/internal/lib/i18n"
+	"lots-service/internal/lib/logger"
+	"lots-service/internal/lib/responseHTTP"
+	"lots-service/pkg/auth"
+	"net/http"
+)
+
+func (h *LotsHandler) InternalGetLot(w http.ResponseWriter, r *http.Request) {
+	lotID, err := ParseLotID(r)
+	if err != nil {
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	lot, err := h.service.GetLotByID(r.Context(), 0, lotID)
+	if err != nil {
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	responseHTTP.JSONResp(w, http.StatusOK, lot)
+}
+
+func (h *LotsHandler) InternalMarkLotSold(w http.ResponseWriter, r *http.Request) {
+	lotID, err := ParseLotID(r)
+	if err != nil {
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	caller, ok := auth.ServiceCallerFromContext(r.Context())
+	if !ok {
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingAPIKey))
+		return
+	}
+
+	if err := h.service.MarkLotSold(r.Context(), lotID); err != nil {
+		logger.FromContext(r.Context()).Info("Помилка позначення лота проданим", "lotID", lotID, "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	logger.FromContext(r.Context()).Info("Лот позначено проданим внутрішнім сервісом", "lotID", lotID, "service", caller.Name)
+
+	responseHTTP.JSONRespMessage(w, r, http.StatusOK, i18n.MsgLotMarkedSold)
+}
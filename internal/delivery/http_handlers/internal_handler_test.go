@@ -0,0 +1,41 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lots-service/internal/lib/responseHTTP"
+	"lots-service/pkg/auth"
+
+	"github.com/gorilla/mux"
+)
+
+// Внутрішній маршрут без middleware API-ключів не має падати на відсутньому
+// сервісі-клієнті в контексті
+func TestInternalMarkLotSoldWithoutAPIKeyMiddleware(t *testing.T) {
+	h := NewLotsHandler(nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/internal/lots/1/sold", nil)
+	req = mux.SetURLVars(req, map[string]string{"lot_id": "1"})
+	rec := httptest.NewRecorder()
+
+	h.InternalMarkLotSold(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	// Внутрішні маршрути авторизуються API-ключем, Bearer-challenge тут зайвий
+	if challenge := rec.Header().Get("WWW-Authenticate"); challenge != "" {
+		t.Fatalf("WWW-Authenticate = %q, want none", challenge)
+	}
+
+	var resp responseHTTP.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if resp.ErrorCode != auth.CodeMissingAPIKey {
+		t.Fatalf("error_code = %q, want %q", resp.ErrorCode, auth.CodeMissingAPIKey)
+	}
+}
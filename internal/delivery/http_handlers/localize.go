@@ -0,0 +1,43 @@
+package http_handlers
+
+import (
+	"lots-service/internal/domain"
+	"lots-service/internal/lib/i18n"
+	"net/http"
+)
+
+// localizeLot підставляє в SaleStatus назву статусу мовою клієнта.
+// Лоти з невідомим статусом повертаються як є.
+func localizeLot(r *http.Request, lot *domain.Lot) {
+	if lot == nil || lot.SaleStatusCode == "" {
+		return
+	}
+
+	lot.SaleStatus = i18n.Message(i18n.FromRequest(r), lot.SaleStatusCode)
+}
+
+func localizeLots(r *http.Request, lots *[]domain.Lot) {
+	if lots == nil {
+		return
+	}
+
+	for i := range *lots {
+		localizeLot(r, &(*lots)[i])
+	}
+}
+
+// parseSaleStatus приймає код статусу або його назву будь-якою мовою
+// і повертає статус у вигляді для збереження в БД
+func parseSaleStatus(value string) string {
+	if status, ok := domain.SaleStatusFromCode(value); ok {
+		return status
+	}
+
+	if key, ok := i18n.KeyOf(value); ok {
+		if status, ok := domain.SaleStatusFromCode(key); ok {
+			return status
+		}
+	}
+
+	return value
+}
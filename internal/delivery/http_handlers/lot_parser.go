@@ -1,22 +1,156 @@
 package http_handlers
 
 import (
-	"fmt"
+	"errors"
+	"io"
 	"lots-service/internal/domain"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// Поле форми з новими файлами зображень
+	newImagesField = "NewImages"
+	// Сумарний ліміт текстових полів multipart-форми в пам'яті
+	maxFormFieldsBytes = 1 << 20
 )
 
+var errFieldsAfterFiles = domain.NewValidation(domain.CodeInvalidRequest, map[string]string{
+	newImagesField: "текстові поля форми мають передувати файлам",
+})
+
+// ParseLotForm розбирає форму лота. Без нових файлів (коли зображення
+// завантажені напряму в storage) клієнт може надіслати звичайну urlencoded форму.
+//
+// Multipart-форма читається потоково: текстові поля потрапляють у r.Form, а на
+// першому файлі розбір зупиняється і решта тіла повертається як ImageStream,
+// який сервіс копіює прямо в storage. Тому поля мають передувати файлам.
+// Без файлів повертається nil.
+func ParseLotForm(r *http.Request) (domain.ImageStream, error) {
+	reader, err := r.MultipartReader()
+	if errors.Is(err, http.ErrNotMultipart) {
+		return nil, formError(r.ParseForm())
+	}
+	if err != nil {
+		return nil, formError(err)
+	}
+
+	r.Form = r.URL.Query()
+	r.PostForm = make(url.Values)
+
+	var size int64
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, formError(err)
+		}
+
+		if part.FileName() != "" {
+			return &imageParts{reader: reader, next: part}, nil
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxFormFieldsBytes-size+1))
+		if err != nil {
+			return nil, formError(err)
+		}
+		size += int64(len(value))
+		if size > maxFormFieldsBytes {
+			return nil, &http.MaxBytesError{Limit: maxFormFieldsBytes}
+		}
+
+		r.Form.Add(part.FormName(), string(value))
+		r.PostForm.Add(part.FormName(), string(value))
+	}
+}
+
+// imageParts віддає файли NewImages з решти multipart-тіла. Файли з інших
+// полів пропускаються, а текстові поля після файлів — помилка.
+type imageParts struct {
+	reader *multipart.Reader
+	next   *multipart.Part
+}
+
+func (p *imageParts) Next() (string, io.Reader, error) {
+	for {
+		part := p.next
+		p.next = nil
+
+		if part == nil {
+			var err error
+			part, err = p.reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				return "", nil, io.EOF
+			}
+			if err != nil {
+				return "", nil, formError(err)
+			}
+		}
+
+		if part.FileName() == "" {
+			return "", nil, errFieldsAfterFiles
+		}
+		if part.FormName() == newImagesField {
+			return part.FileName(), part, nil
+		}
+	}
+}
+
+// formError лишає як є перевищення ліміту тіла (413) і помилки предметної
+// області, а пошкоджену форму перетворює на invalid_request
+func formError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var tooLarge *http.MaxBytesError
+	var domainErr *domain.Error
+	if errors.As(err, &tooLarge) || errors.As(err, &domainErr) {
+		return err
+	}
+
+	return domain.NewValidation(domain.CodeInvalidRequest, nil).Wrap(err)
+}
+
+func ParseUploadedImages(r *http.Request) domain.UploadedImages {
+	return domain.UploadedImages{
+		SessionToken: r.FormValue("UploadSessionToken"),
+		ImageIDs:     r.Form["UploadedImages"],
+	}
+}
+
+// ParseLotID читає lot_id з шляху запиту
+func ParseLotID(r *http.Request) (int, error) {
+	lotID, err := strconv.Atoi(mux.Vars(r)["lot_id"])
+	if err != nil {
+		return 0, domain.NewValidation(domain.CodeInvalidLotID, map[string]string{"lot_id": "not_a_number"}).Wrap(err)
+	}
+
+	return lotID, nil
+}
+
 func ParseLotFromRequest(r *http.Request) (domain.Lot, error) {
-	parseInt := func(key string) (int, error) {
+	fields := make(map[string]string)
+
+	parseInt := func(key string) int {
 		val := r.FormValue(key)
 		if val == "" || val == "null" || val == "undefined" {
-			return 0, nil
+			return 0
+		}
+
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			fields[key] = "not_a_number"
 		}
-		return strconv.Atoi(val)
+		return n
 	}
 
-	var err error
 	var lot domain.Lot
 
 	lot.Car.Brand = r.FormValue("Brand")
@@ -27,17 +161,11 @@ func ParseLotFromRequest(r *http.Request) (domain.Lot, error) {
 	lot.Car.Color = r.FormValue("Color")
 	lot.Car.VinCode = r.FormValue("VinCode")
 	lot.Description = r.FormValue("Description")
-	lot.SaleStatus = r.FormValue("SaleStatus")
+	lot.SaleStatus = parseSaleStatus(r.FormValue("SaleStatus"))
 
-	if lot.Car.MadeYear, err = parseInt("MadeYear"); err != nil {
-		return lot, fmt.Errorf("bad MadeYear: %w", err)
-	}
-	if lot.Car.Mileage, err = parseInt("Mileage"); err != nil {
-		return lot, fmt.Errorf("bad Mileage: %w", err)
-	}
-	if lot.SalePrice, err = parseInt("SalePrice"); err != nil {
-		return lot, fmt.Errorf("bad SalePrice: %w", err)
-	}
+	lot.Car.MadeYear = parseInt("MadeYear")
+	lot.Car.Mileage = parseInt("Mileage")
+	lot.SalePrice = parseInt("SalePrice")
 	// if lot.Car.CarID, err = parseInt("CarID"); err != nil {
 	// 	return lot, fmt.Errorf("bad CarID: %w", err)
 	// }
@@ -48,5 +176,9 @@ func ParseLotFromRequest(r *http.Request) (domain.Lot, error) {
 	// 	return lot, fmt.Errorf("bad ModelID: %w", err)
 	// }
 
+	if len(fields) > 0 {
+		return lot, domain.NewValidation(domain.CodeInvalidLot, fields)
+	}
+
 	return lot, nil
 }
@@ -0,0 +1,162 @@
+package http_handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lots-service/internal/domain"
+	"lots-service/internal/lib/responseHTTP"
+	"lots-service/internal/service"
+)
+
+// zeroReader віддає нескінченний потік нулів без алокацій
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// newLotRequest будує multipart-запит лота, тіло якого генерується на льоту,
+// тож сам тест не тримає файл у пам'яті
+func newLotRequest(fileSize int64) *http.Request {
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
+
+	go func() {
+		err := writer.WriteField("Brand", "BMW")
+		if err == nil {
+			var part io.Writer
+			part, err = writer.CreateFormFile(newImagesField, "photo.jpg")
+			if err == nil {
+				_, err = io.Copy(part, io.LimitReader(zeroReader{}, fileSize))
+			}
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		pipeWriter.CloseWithError(err)
+	}()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/lots/create_lot", pipeReader)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req
+}
+
+func newDiscardStorage(tb testing.TB) *httptest.Server {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.Copy(io.Discard, r.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	tb.Cleanup(storage.Close)
+
+	return storage
+}
+
+func saveLotImages(tb testing.TB, lotsService *service.LotsService, fileSize int64) []string {
+	req := newLotRequest(fileSize)
+
+	files, err := ParseLotForm(req)
+	if err != nil {
+		tb.Fatalf("ParseLotForm: %v", err)
+	}
+	if got := req.FormValue("Brand"); got != "BMW" {
+		tb.Fatalf("Brand = %q, want BMW", got)
+	}
+
+	names, err := lotsService.SaveImages(context.Background(), files)
+	if err != nil {
+		tb.Fatalf("SaveImages: %v", err)
+	}
+
+	return names
+}
+
+func TestParseLotFormStreamsImages(t *testing.T) {
+	storage := newDiscardStorage(t)
+	lotsService := service.NewLotsService(nil, storage.URL, nil, nil)
+
+	names := saveLotImages(t, lotsService, 1<<20)
+	if len(names) != 1 {
+		t.Fatalf("saved %d images, want 1", len(names))
+	}
+}
+
+// storage, що відповідає успіхом, не дочитавши файли, не має давати лот
+// з частиною зображень
+func TestSaveImagesFailsWhenStorageSkipsBody(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer storage.Close()
+	lotsService := service.NewLotsService(nil, storage.URL, nil, nil)
+
+	files, err := ParseLotForm(newLotRequest(32 << 20))
+	if err != nil {
+		t.Fatalf("ParseLotForm: %v", err)
+	}
+
+	if names, err := lotsService.SaveImages(context.Background(), files); err == nil {
+		t.Fatalf("SaveImages = %v, want error for the unread upload", names)
+	}
+}
+
+func TestFieldsAfterFilesKeepDetails(t *testing.T) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile(newImagesField, "photo.jpg")
+	part.Write([]byte("jpeg"))
+	writer.WriteField("Brand", "BMW")
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/lots/create_lot", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	files, err := ParseLotForm(req)
+	if err != nil {
+		t.Fatalf("ParseLotForm: %v", err)
+	}
+
+	lotsService := service.NewLotsService(nil, newDiscardStorage(t).URL, nil, nil)
+	_, err = lotsService.SaveImages(context.Background(), files)
+
+	rec := httptest.NewRecorder()
+	responseHTTP.WriteError(rec, req, err)
+
+	var resp responseHTTP.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if rec.Code != http.StatusUnprocessableEntity || resp.ErrorCode != domain.CodeInvalidRequest || resp.Fields[newImagesField] == "" {
+		t.Fatalf("got %d %+v, want 422 invalid_request with %s details", rec.Code, resp, newImagesField)
+	}
+}
+
+// BenchmarkSaveImages показує, що пам'ять на запит не росте разом з розміром
+// файлу: B/op має лишатися приблизно однаковим для всіх розмірів
+func BenchmarkSaveImages(b *testing.B) {
+	storage := newDiscardStorage(b)
+	lotsService := service.NewLotsService(nil, storage.URL, nil, nil)
+
+	for _, size := range []int64{1 << 20, 16 << 20, 64 << 20, 256 << 20} {
+		b.Run(fmt.Sprintf("%dMiB", size>>20), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(size)
+
+			for i := 0; i < b.N; i++ {
+				saveLotImages(b, lotsService, size)
+			}
+		})
+	}
+}
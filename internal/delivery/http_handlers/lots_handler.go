@@ -2,14 +2,14 @@ package http_handlers
 
 import (
 	"encoding/json"
-	"log/slog"
 	"lots-service/internal/domain"
+	"lots-service/internal/lib/i18n"
+	"lots-service/internal/lib/logger"
 	"lots-service/internal/lib/responseHTTP"
 	"lots-service/internal/service"
+	"lots-service/pkg/auth"
 	"net/http"
 	"strconv"
-
-	"github.com/gorilla/mux"
 )
 
 type LotsHandler struct {
@@ -21,10 +21,10 @@ func NewLotsHandler(service *service.LotsService) *LotsHandler {
 }
 
 func (h *LotsHandler) GetLotsCount(w http.ResponseWriter, r *http.Request) {
-	lotsCount, err := h.service.GetLotsCount()
+	lotsCount, err := h.service.GetLotsCount(r.Context())
 	if err != nil {
-		slog.Debug("Кількість 0, лоти не знайдені", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusNotFound, "Лоти не знайдені")
+		logger.FromContext(r.Context()).Debug("Кількість 0, лоти не знайдені", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
@@ -41,9 +41,9 @@ func (h *LotsHandler) GetLotsByParamsCount(w http.ResponseWriter, r *http.Reques
 	minYear := params.Get("minYear")
 	maxYear := params.Get("maxYear")
 
-	lotsCount, err := h.service.GetLotsByParamsCount(brand, model, minPrice, maxPrice, minYear, maxYear)
+	lotsCount, err := h.service.GetLotsByParamsCount(r.Context(), brand, model, minPrice, maxPrice, minYear, maxYear)
 	if err != nil {
-		responseHTTP.JSONError(w, http.StatusNotFound, "Лоти за вказаними параметрами не знайдені")
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
@@ -62,52 +62,43 @@ func (h *LotsHandler) GetLotsByParamsCount(w http.ResponseWriter, r *http.Reques
 // @Failure		500	{object}	responseHTTP.ErrorResponse
 // @Router			/api/lots/id/{lot_id} [get]
 func (h *LotsHandler) GetLotByID(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("user_id").(int)
-	if !ok {
-		responseHTTP.JSONError(w, http.StatusUnauthorized, "Не авторизовано")
-		return
-	}
+	// Маршрут з необов'язковою авторизацією: анонімний запит має userID 0
+	userID, _ := auth.UserIDFromContext(r.Context())
 
-	vars := mux.Vars(r)
-	lotID, err := strconv.Atoi(vars["lot_id"])
+	lotID, err := ParseLotID(r)
 	if err != nil {
-		responseHTTP.JSONError(w, http.StatusBadRequest, "Некоректний ID лота")
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
-	lot, err := h.service.GetLotByID(userID, lotID)
+	lot, err := h.service.GetLotByID(r.Context(), userID, lotID)
 	if err != nil {
-		responseHTTP.JSONError(w, http.StatusNotFound, "Лот не знайдено")
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
+	localizeLot(r, lot)
 	responseHTTP.JSONResp(w, http.StatusOK, lot)
 }
 
 func (h *LotsHandler) GetLotsPage(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("user_id").(int)
-	if !ok {
-		responseHTTP.JSONError(w, http.StatusUnauthorized, "Не авторизовано")
-		return
-	}
+	userID, _ := auth.UserIDFromContext(r.Context())
 
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
 
-	lots, err := h.service.GetPageLots(userID, page, limit)
+	lots, err := h.service.GetPageLots(r.Context(), userID, page, limit)
 	if err != nil {
-		responseHTTP.JSONError(w, http.StatusNotFound, "Лоти не знайдені")
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
+	localizeLots(r, lots)
 	responseHTTP.JSONResp(w, http.StatusOK, lots)
 }
 
 func (h *LotsHandler) GetLotsPageByParams(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("user_id").(int)
-	if !ok {
-		userID = 0
-	}
+	userID, _ := auth.UserIDFromContext(r.Context())
 
 	params := r.URL.Query()
 
@@ -136,15 +127,15 @@ func (h *LotsHandler) GetLotsPageByParams(w http.ResponseWriter, r *http.Request
 		}
 	}
 
-	lots, total, err := h.service.GetLotsByParams(userID, page, limit, brand, model, minPrice, maxPrice, minYear, maxYear)
+	lots, total, err := h.service.GetLotsByParams(r.Context(), userID, page, limit, brand, model, minPrice, maxPrice, minYear, maxYear)
 	if err != nil {
-		slog.Debug("Помилка при отриманні лотів з БД", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusInternalServerError, "Помилка на сервері")
+		logger.FromContext(r.Context()).Debug("Помилка при отриманні лотів з БД", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
 	if total == 0 {
-		slog.Debug("Лоти за параметрами не знайдені", "brand", brand, "model", model, "minPrice", minPrice,
+		logger.FromContext(r.Context()).Debug("Лоти за параметрами не знайдені", "brand", brand, "model", model, "minPrice", minPrice,
 			"maxPrice", maxPrice, "minYear", minYear, "maxYear", maxYear)
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(domain.LotsResponse{Lots: []domain.Lot{}, Total: 0})
@@ -159,17 +150,16 @@ func (h *LotsHandler) GetLotsPageByParams(w http.ResponseWriter, r *http.Request
 	if response.Lots == nil {
 		response.Lots = []domain.Lot{}
 	}
-
-	slog.Debug("Запит " + r.RequestURI)
+	localizeLots(r, &response.Lots)
 
 	responseHTTP.JSONResp(w, http.StatusOK, response)
 }
 
 func (h *LotsHandler) GetBrands(w http.ResponseWriter, r *http.Request) {
-	brands, err := h.service.GetBrands()
+	brands, err := h.service.GetBrands(r.Context())
 	if err != nil {
-		slog.Debug("Бренди не знайдені", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusNotFound, "Бренди не знайдені")
+		logger.FromContext(r.Context()).Debug("Бренди не знайдені", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
@@ -179,10 +169,10 @@ func (h *LotsHandler) GetBrands(w http.ResponseWriter, r *http.Request) {
 func (h *LotsHandler) GetModels(w http.ResponseWriter, r *http.Request) {
 	brandName := r.URL.Query().Get("brand")
 
-	models, err := h.service.GetModels(brandName)
+	models, err := h.service.GetModels(r.Context(), brandName)
 	if err != nil {
-		slog.Debug("Моделі не знайдені", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusNotFound, "Моделі не знайдені")
+		logger.FromContext(r.Context()).Debug("Моделі не знайдені", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
@@ -190,109 +180,106 @@ func (h *LotsHandler) GetModels(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *LotsHandler) CreateLot(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("user_id").(int)
+	userID, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
-		responseHTTP.JSONError(w, http.StatusUnauthorized, "Не авторизовано")
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
 		return
 	}
 
-	if err := r.ParseMultipartForm(32 << 20); err != nil {
-		slog.Debug("Помилка парсингу форми", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusUnprocessableEntity, "Помилка парсингу форми")
+	files, err := ParseLotForm(r)
+	if err != nil {
+		logger.FromContext(r.Context()).Debug("Помилка парсингу форми", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
 	lot, err := ParseLotFromRequest(r)
 	if err != nil {
-		slog.Debug("Помилка валідації лота", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusUnprocessableEntity, "Помилка валідації форми")
+		logger.FromContext(r.Context()).Debug("Помилка валідації лота", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 	lot.SellerID = userID
 
-	files := r.MultipartForm.File["NewImages"]
+	uploaded := ParseUploadedImages(r)
 
-	if err := h.service.CreateLot(r.Context(), &lot, files); err != nil {
-		slog.Debug("Помилка збереження лота", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusInternalServerError, "Помилка на сервері")
+	if err := h.service.CreateLot(r.Context(), &lot, files, uploaded); err != nil {
+		logger.FromContext(r.Context()).Debug("Помилка збереження лота", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
-	slog.Debug("Створено лот")
+	logger.FromContext(r.Context()).Debug("Створено лот")
 
-	responseHTTP.JSONRespMessage(w, http.StatusCreated, "Лот створено")
+	responseHTTP.JSONRespMessage(w, r, http.StatusCreated, i18n.MsgLotCreated)
 }
 
 func (h *LotsHandler) UpdateLot(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("user_id").(int)
+	userID, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
-		responseHTTP.JSONError(w, http.StatusUnauthorized, "Не авторизовано")
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
 		return
 	}
 
-	vars := mux.Vars(r)
-	lotID, err := strconv.Atoi(vars["lot_id"])
+	lotID, err := ParseLotID(r)
 	if err != nil {
-		slog.Debug("Некоректний ID лота", "lotID", lotID, "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusBadRequest, "Некоректний ID лота")
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
-	err = r.ParseMultipartForm(32 << 20)
+	files, err := ParseLotForm(r)
 	if err != nil {
-		slog.Debug("Помилка парсингу форми", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusUnprocessableEntity, "Помилка парсингу форми")
+		logger.FromContext(r.Context()).Debug("Помилка парсингу форми", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
 	lot, err := ParseLotFromRequest(r)
 	if err != nil {
-		responseHTTP.JSONError(w, http.StatusBadRequest, "Помилка парсингу лота з форми")
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 	lot.LotID = lotID
 	lot.SellerID = userID
 
-	files := r.MultipartForm.File["NewImages"]
-	deleteImages := r.MultipartForm.Value["DeleteImagesNames"]
-	oldImagesStr := r.MultipartForm.Value["OldImagesNames"]
+	uploaded := ParseUploadedImages(r)
+	deleteImages := r.Form["DeleteImagesNames"]
+	oldImagesStr := r.Form["OldImagesNames"]
 
-	slog.Debug("Оновлення лота", "lotID", lotID, "userID", userID, "deleteImages", deleteImages, "oldImagesStr", oldImagesStr, "files", files)
+	logger.FromContext(r.Context()).Debug("Оновлення лота", "lotID", lotID, "userID", userID, "deleteImages", deleteImages, "oldImagesStr", oldImagesStr)
 
-	if err := h.service.UpdateLot(r.Context(), &lot, files, deleteImages, oldImagesStr); err != nil {
-		slog.Debug("Помилка при оновленні лота", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusInternalServerError, "Помилка на сервері")
+	if err := h.service.UpdateLot(r.Context(), &lot, files, uploaded, deleteImages, oldImagesStr); err != nil {
+		logger.FromContext(r.Context()).Debug("Помилка при оновленні лота", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
-	slog.Debug("Оновлено лот", "lotID", lotID)
+	logger.FromContext(r.Context()).Debug("Оновлено лот", "lotID", lotID)
 
-	responseHTTP.JSONRespMessage(w, http.StatusOK, "Лот оновлено")
+	responseHTTP.JSONRespMessage(w, r, http.StatusOK, i18n.MsgLotUpdated)
 }
 
 func (h *LotsHandler) DeleteLot(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("user_id").(int)
+	userID, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
-		responseHTTP.JSONError(w, http.StatusUnauthorized, "Не авторизовано")
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
 		return
 	}
 
-	vars := mux.Vars(r)
-	lotID, err := strconv.Atoi(vars["lot_id"])
+	lotID, err := ParseLotID(r)
 	if err != nil {
-		slog.Debug("Некоректний ID лота", "lot_id", lotID, "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusBadRequest, "Некоректний ID лота")
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
 	err = h.service.DeleteLot(r.Context(), lotID, userID)
 	if err != nil {
-		slog.Debug("Помилка видалення лота", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusInternalServerError, "Помилка на сервері")
+		logger.FromContext(r.Context()).Debug("Помилка видалення лота", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
-	slog.Debug("Видалено лот користувачем", "userID", userID, "lotID", lotID)
+	logger.FromContext(r.Context()).Debug("Видалено лот користувачем", "userID", userID, "lotID", lotID)
 
-	responseHTTP.JSONRespMessage(w, http.StatusOK, "Лот видалено")
+	responseHTTP.JSONRespMessage(w, r, http.StatusOK, i18n.MsgLotDeleted)
 }
@@ -0,0 +1,63 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"lots-service/internal/domain"
+	"lots-service/internal/lib/i18n"
+	"lots-service/internal/lib/logger"
+	"lots-service/internal/lib/responseHTTP"
+	"lots-service/pkg/auth"
+	"net/http"
+	"time"
+)
+
+type revocationRequest struct {
+	TokenID   string    `json:"token_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UserID    int       `json:"user_id"`
+}
+
+// RevocationHandler приймає відкликання від сервісу авторизації:
+// {"token_id": "...", "expires_at": "..."} або {"user_id": 1}
+func RevocationHandler(revoker auth.Revoker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req revocationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			responseHTTP.WriteError(w, r, domain.NewValidation(domain.CodeInvalidRequest, nil).Wrap(err))
+			return
+		}
+
+		if req.TokenID == "" && req.UserID == 0 {
+			responseHTTP.WriteError(w, r, domain.NewValidation(domain.CodeInvalidRequest, map[string]string{
+				"token_id": "потрібно вказати token_id або user_id",
+			}))
+			return
+		}
+
+		if req.TokenID != "" {
+			if req.ExpiresAt.IsZero() {
+				responseHTTP.WriteError(w, r, domain.NewValidation(domain.CodeInvalidRequest, map[string]string{
+					"expires_at": "обов'язковий для token_id",
+				}))
+				return
+			}
+
+			if err := revoker.RevokeToken(r.Context(), req.TokenID, req.ExpiresAt); err != nil {
+				responseHTTP.WriteError(w, r, fmt.Errorf("відкликання токена: %w", err))
+				return
+			}
+		}
+
+		if req.UserID != 0 {
+			if err := revoker.RevokeUser(r.Context(), req.UserID, time.Now()); err != nil {
+				responseHTTP.WriteError(w, r, fmt.Errorf("відкликання токенів користувача: %w", err))
+				return
+			}
+		}
+
+		logger.FromContext(r.Context()).Info("Відкликано доступ", "tokenID", req.TokenID, "userID", req.UserID)
+
+		responseHTTP.JSONRespMessage(w, r, http.StatusOK, i18n.MsgAccessRevoked)
+	}
+}
@@ -0,0 +1,59 @@
+package http_handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lots-service/internal/domain"
+	"lots-service/internal/lib/responseHTTP"
+)
+
+type fakeRevoker struct {
+	err error
+}
+
+func (r fakeRevoker) RevokeToken(context.Context, string, time.Time) error { return r.err }
+
+func (r fakeRevoker) RevokeUser(context.Context, int, time.Time) error { return r.err }
+
+func TestRevocationHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		revoker    fakeRevoker
+		wantStatus int
+		wantCode   string
+		wantField  string
+	}{
+		{"bad json", `{`, fakeRevoker{}, http.StatusUnprocessableEntity, domain.CodeInvalidRequest, ""},
+		{"no target", `{}`, fakeRevoker{}, http.StatusUnprocessableEntity, domain.CodeInvalidRequest, "token_id"},
+		{"token without expires_at", `{"token_id": "t"}`, fakeRevoker{}, http.StatusUnprocessableEntity, domain.CodeInvalidRequest, "expires_at"},
+		{"store down", `{"user_id": 7}`, fakeRevoker{err: errors.New("db is down")}, http.StatusInternalServerError, domain.CodeInternal, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/internal/auth/revocations", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			RevocationHandler(tt.revoker)(rec, req)
+
+			var resp responseHTTP.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if rec.Code != tt.wantStatus || resp.ErrorCode != tt.wantCode {
+				t.Fatalf("got %d %q, want %d %q", rec.Code, resp.ErrorCode, tt.wantStatus, tt.wantCode)
+			}
+			if tt.wantField != "" && resp.Fields[tt.wantField] == "" {
+				t.Fatalf("fields = %v, want %s", resp.Fields, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"lots-service/internal/domain"
+	"lots-service/internal/lib/logger"
+	"lots-service/internal/lib/responseHTTP"
+	"lots-service/pkg/auth"
+	"net/http"
+)
+
+type uploadSessionRequest struct {
+	Extensions []string `json:"extensions"`
+}
+
+// CreateUploadSession видає слоти для прямого завантаження зображень у storage.
+// Отримані image_id разом із session_token передаються в create_lot/update_lot
+// полями UploadedImages та UploadSessionToken.
+func (h *LotsHandler) CreateUploadSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
+		return
+	}
+
+	var req uploadSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.FromContext(r.Context()).Debug("Помилка декодування запиту", "err", err.Error())
+		responseHTTP.WriteError(w, r, domain.NewValidation(domain.CodeInvalidRequest, nil).Wrap(err))
+		return
+	}
+
+	session, err := h.service.CreateUploadSession(r.Context(), userID, req.Extensions)
+	if err != nil {
+		logger.FromContext(r.Context()).Debug("Помилка створення сесії завантаження", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
+		return
+	}
+
+	responseHTTP.JSONResp(w, http.StatusCreated, session)
+}
@@ -1,55 +1,66 @@
 package http_handlers
 
 import (
-	"log/slog"
+	"lots-service/internal/lib/i18n"
+	"lots-service/internal/lib/logger"
 	"lots-service/internal/lib/responseHTTP"
+	"lots-service/pkg/auth"
 	"net/http"
-	"strconv"
-
-	"github.com/gorilla/mux"
 )
 
 func (h *LotsHandler) GetUserPostedLots(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("user_id").(int)
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
+		return
+	}
 
-	postedLots, err := h.service.GetUserPostedLots(userID)
+	postedLots, err := h.service.GetUserPostedLots(r.Context(), userID)
 	if err != nil {
-		slog.Debug("Опубликовані користувачем лоти не знайдені", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusNotFound, "Опубликовані користувачем лоти не знайдені")
+		logger.FromContext(r.Context()).Debug("Опубликовані користувачем лоти не знайдені", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
+	localizeLots(r, postedLots)
 	responseHTTP.JSONResp(w, http.StatusOK, postedLots)
 }
 
 func (h *LotsHandler) GetUserLikedLots(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("user_id").(int)
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
+		return
+	}
 
-	LikedLots, err := h.service.GetUserLikedLots(userID)
+	LikedLots, err := h.service.GetUserLikedLots(r.Context(), userID)
 	if err != nil {
-		slog.Debug("Лайкнуті користувачем лоти не знайдені", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusNotFound, "Лайкнуті користувачем лоти не знайдені")
+		logger.FromContext(r.Context()).Debug("Лайкнуті користувачем лоти не знайдені", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
+	localizeLots(r, LikedLots)
 	responseHTTP.JSONResp(w, http.StatusOK, LikedLots)
 }
 
 func (h *LotsHandler) LikeLot(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("user_id").(int)
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
+		return
+	}
 
-	vars := mux.Vars(r)
-	lotID, err := strconv.Atoi(vars["lot_id"])
+	lotID, err := ParseLotID(r)
 	if err != nil {
-		slog.Debug("Некоректний ID лота", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusBadRequest, "Некоректний ID лота")
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
-	err = h.service.LikeLot(userID, lotID)
+	err = h.service.LikeLot(r.Context(), userID, lotID)
 	if err != nil {
-		slog.Debug("Помилка встановлення лайку", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusInternalServerError, "Не вдалося додати лайк")
+		logger.FromContext(r.Context()).Debug("Помилка встановлення лайку", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
@@ -57,20 +68,22 @@ func (h *LotsHandler) LikeLot(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *LotsHandler) UnlikeLot(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("user_id").(int)
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
+		return
+	}
 
-	vars := mux.Vars(r)
-	lotID, err := strconv.Atoi(vars["lot_id"])
+	lotID, err := ParseLotID(r)
 	if err != nil {
-		slog.Debug("Некоректний ID лота", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusBadRequest, "Некоректний ID лота")
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
-	err = h.service.UnlikeLot(userID, lotID)
+	err = h.service.UnlikeLot(r.Context(), userID, lotID)
 	if err != nil {
-		slog.Debug("Помилка прибирання лайку", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusInternalServerError, "Не вдалося прибрати лайк")
+		logger.FromContext(r.Context()).Debug("Помилка прибирання лайку", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
@@ -78,24 +91,26 @@ func (h *LotsHandler) UnlikeLot(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *LotsHandler) BuyLotHandler(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("user_id").(int)
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		responseHTTP.WriteError(w, r, auth.NewError(auth.ErrMissingToken))
+		return
+	}
 
-	vars := mux.Vars(r)
-	lotID, err := strconv.Atoi(vars["lot_id"])
+	lotID, err := ParseLotID(r)
 	if err != nil {
-		slog.Info("Некоректний ID лота", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusBadRequest, "Некоректний ID лота")
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
-	err = h.service.BuyLot(userID, lotID)
+	err = h.service.BuyLot(r.Context(), userID, lotID)
 	if err != nil {
-		slog.Info("Помилка при купівлі лота", "err", err.Error())
-		responseHTTP.JSONError(w, http.StatusBadRequest, "Неможливо купити лот")
+		logger.FromContext(r.Context()).Info("Помилка при купівлі лота", "err", err.Error())
+		responseHTTP.WriteError(w, r, err)
 		return
 	}
 
-	slog.Debug("Куплено лот користувачем", "userID:", userID, "lotID:", lotID)
+	logger.FromContext(r.Context()).Debug("Куплено лот користувачем", "userID:", userID, "lotID:", lotID)
 
-	responseHTTP.JSONRespMessage(w, http.StatusOK, "Лот успішно куплено")
+	responseHTTP.JSONRespMessage(w, r, http.StatusOK, i18n.MsgLotBought)
 }
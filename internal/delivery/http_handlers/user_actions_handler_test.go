@@ -0,0 +1,64 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"lots-service/internal/lib/responseHTTP"
+	"lots-service/pkg/auth"
+
+	"github.com/gorilla/mux"
+)
+
+// Маршрут, зареєстрований без auth-middleware, не має падати на відсутньому
+// користувачі в контексті, а має відповідати 401 у звичайному JSON-форматі
+func TestUserActionsWithoutAuthMiddleware(t *testing.T) {
+	h := NewLotsHandler(nil)
+
+	tests := []struct {
+		name    string
+		method  string
+		handler http.HandlerFunc
+	}{
+		{"GetUserPostedLots", http.MethodGet, h.GetUserPostedLots},
+		{"GetUserLikedLots", http.MethodGet, h.GetUserLikedLots},
+		{"LikeLot", http.MethodPost, h.LikeLot},
+		{"UnlikeLot", http.MethodDelete, h.UnlikeLot},
+		{"BuyLotHandler", http.MethodPost, h.BuyLotHandler},
+		{"CreateLot", http.MethodPost, h.CreateLot},
+		{"UpdateLot", http.MethodPut, h.UpdateLot},
+		{"DeleteLot", http.MethodDelete, h.DeleteLot},
+		{"CreateUploadSession", http.MethodPost, h.CreateUploadSession},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/lots/1", nil)
+			req = mux.SetURLVars(req, map[string]string{"lot_id": "1"})
+			rec := httptest.NewRecorder()
+
+			tt.handler(rec, req)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+			if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+				t.Fatalf("Content-Type = %q, want JSON", ct)
+			}
+			if challenge := rec.Header().Get("WWW-Authenticate"); !strings.HasPrefix(challenge, "Bearer ") {
+				t.Fatalf("WWW-Authenticate = %q, want Bearer challenge", challenge)
+			}
+
+			var resp responseHTTP.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if resp.Code != http.StatusUnauthorized || resp.ErrorCode != auth.CodeMissingToken || resp.Message == "" {
+				t.Fatalf("body = %+v, want 401 missing_token with message", resp)
+			}
+		})
+	}
+}
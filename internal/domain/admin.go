@@ -0,0 +1,15 @@
+package domain
+
+const (
+	AdminActionDeleteLot = "delete_lot"
+	AdminActionHideLot   = "hide_lot"
+	AdminActionUnhideLot = "unhide_lot"
+	AdminActionUpdateLot = "update_lot"
+)
+
+type AdminAction struct {
+	AdminID int
+	LotID   int
+	Action  string
+	Reason  string
+}
@@ -0,0 +1,89 @@
+package domain
+
+import "errors"
+
+// Види помилок, за якими responseHTTP обирає HTTP-статус
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrForbidden   = errors.New("forbidden")
+	ErrConflict    = errors.New("conflict")
+	ErrValidation  = errors.New("validation failed")
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// Стабільні машинні коди помилок, які отримує клієнт
+const (
+	CodeInternal         = "internal_error"
+	CodeInvalidRequest   = "invalid_request"
+	CodeInvalidLotID     = "invalid_lot_id"
+	CodeInvalidLot       = "invalid_lot"
+	CodeLotNotFound      = "lot_not_found"
+	CodeNotLotOwner      = "not_lot_owner"
+	CodeImageNotOwned    = "image_not_owned"
+	CodeLotAlreadySold   = "lot_already_sold"
+	CodeInvalidUpload    = "invalid_upload"
+	CodeUploadsDisabled  = "uploads_disabled"
+	CodeAlreadyExists    = "already_exists"
+	CodeInvalidReference = "invalid_reference"
+	CodeConcurrentUpdate = "concurrent_update"
+	CodeRequestTooLarge  = "request_too_large"
+	CodeImageAlreadyUsed = "image_already_used"
+	CodeTimeout          = "timeout"
+)
+
+// Error — помилка предметної області. Kind визначає HTTP-статус, Code
+// передається клієнту, а Detail і Err потрапляють лише в логи.
+type Error struct {
+	Kind   error
+	Code   string
+	Detail string
+	// Для помилок валідації: поле -> код помилки поля
+	Fields map[string]string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	msg := e.Code
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+// Wrap зберігає причину помилки для errors.Is/As та логів
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func NewNotFound(code, detail string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Detail: detail}
+}
+
+func NewForbidden(code, detail string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Detail: detail}
+}
+
+func NewConflict(code, detail string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Detail: detail}
+}
+
+func NewValidation(code string, fields map[string]string) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Fields: fields}
+}
+
+func NewUnavailable(code, detail string) *Error {
+	return &Error{Kind: ErrUnavailable, Code: code, Detail: detail}
+}
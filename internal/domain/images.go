@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"io"
+	"time"
+)
+
+type StoredImage struct {
+	Name       string    `json:"name"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+type ImagesReconcileReport struct {
+	Orphans  []string
+	Deleted  []string
+	Skipped  []string
+	Dangling map[int][]string
+	DryRun   bool
+}
+
+type UploadSlot struct {
+	ImageID   string `json:"image_id"`
+	UploadURL string `json:"upload_url"`
+}
+
+type UploadSessionResponse struct {
+	SessionToken string       `json:"session_token"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	Slots        []UploadSlot `json:"slots"`
+}
+
+// UploadedImages — зображення, які клієнт завантажив напряму в storage
+// і підтверджує при створенні або оновленні лота
+type UploadedImages struct {
+	SessionToken string
+	ImageIDs     []string
+}
+
+// UploadClaim — перевірені зображення з сесії прямого завантаження. Репозиторій
+// позначає їх використаними в транзакції запису лота, тож невдалий запис
+// лишає їх доступними для повторної спроби.
+type UploadClaim struct {
+	UserID   int
+	ImageIDs []string
+}
+
+// ImageStream віддає файли зображень по одному в міру читання тіла запиту,
+// не буферизуючи їх. Next повертає io.EOF, коли файлів більше немає.
+type ImageStream interface {
+	Next() (filename string, content io.Reader, err error)
+}
@@ -29,35 +29,48 @@ type Car struct {
 }
 
 type Lot struct {
-	LotID       int
-	SellerID    int
-	Car         Car
-	PostDate    string
-	SalePrice   int
-	SaleStatus  string
-	Description string
-	IsLiked     bool
-	Images      []string
+	LotID      int
+	SellerID   int
+	Car        Car
+	PostDate   string
+	SalePrice  int
+	SaleStatus string
+	// Стабільний код статусу (for_sale, sold); SaleStatus локалізується за Accept-Language
+	SaleStatusCode string
+	Description    string
+	IsLiked        bool
+	IsHidden       bool
+	Images         []string
+	ImageURLs      []string
 }
 
 type LotsRepository interface {
-	GetLotsCount() (int, error)
-	GetLotsByParamsCount(brand, model, minPrice, maxPrice, minYear, maxYear string) (int, error)
-	GetLotByID(userID, lotID int) (*Lot, error)
-	GetLotsByParams(userID int, page, limit int, brand, model, minPrice, maxPrice, minYear, maxYear string) (*[]Lot, int, error)
+	GetLotsCount(ctx context.Context) (int, error)
+	GetLotsByParamsCount(ctx context.Context, brand, model, minPrice, maxPrice, minYear, maxYear string) (int, error)
+	GetLotByID(ctx context.Context, userID, lotID int) (*Lot, error)
+	GetLotsByParams(ctx context.Context, userID int, page, limit int, brand, model, minPrice, maxPrice, minYear, maxYear string) (*[]Lot, int, error)
 
-	GetBrands() (*[]Brand, error)
-	GetModels(brandName string) (*[]Model, error)
+	GetBrands(ctx context.Context) (*[]Brand, error)
+	GetModels(ctx context.Context, brandName string) (*[]Model, error)
 
-	GetUserPostedLots(userID int) (*[]Lot, error)
-	GetUserLikedLots(userID int) (*[]Lot, error)
+	GetUserPostedLots(ctx context.Context, userID int) (*[]Lot, error)
+	GetUserLikedLots(ctx context.Context, userID int) (*[]Lot, error)
 
-	CreateLot(ctx context.Context, lot *Lot) error
-	UpdateLot(ctx context.Context, lot *Lot) error
-	DeleteLot(ctx context.Context, lotID int) error
+	// audit у змінах лота — запис журналу admin_actions, що пишеться в тій самій
+	// транзакції, тож дія адміністратора не відбудеться без запису про неї.
+	// Для дій власника лота audit дорівнює nil.
+	// uploads позначаються використаними в тій самій транзакції; повторне
+	// використання будь-якого з них відхиляє зміну цілком.
+	CreateLot(ctx context.Context, lot *Lot, uploads *UploadClaim) error
+	UpdateLot(ctx context.Context, lot *Lot, uploads *UploadClaim, audit *AdminAction) error
+	DeleteLot(ctx context.Context, lotID int, audit *AdminAction) error
 
-	LikeLot(userID, lotID int) error
-	UnlikeLot(userID, lotID int) error
+	LikeLot(ctx context.Context, userID, lotID int) error
+	UnlikeLot(ctx context.Context, userID, lotID int) error
 
-	MarkLotAsSold(lotID int) error
+	MarkLotAsSold(ctx context.Context, lotID int, visibleOnly bool) error
+
+	GetLotsImages(ctx context.Context) (map[int][]string, error)
+
+	SetLotHidden(ctx context.Context, lotID int, hidden bool, audit *AdminAction) error
 }
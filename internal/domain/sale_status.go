@@ -0,0 +1,34 @@
+package domain
+
+// Статуси продажу в тому вигляді, в якому вони зберігаються в БД
+const (
+	SaleStatusForSale = "Продається"
+	SaleStatusSold    = "Продано"
+)
+
+// Стабільні коди статусів для клієнтів API
+const (
+	SaleStatusCodeForSale = "for_sale"
+	SaleStatusCodeSold    = "sold"
+)
+
+var saleStatusCodes = map[string]string{
+	SaleStatusForSale: SaleStatusCodeForSale,
+	SaleStatusSold:    SaleStatusCodeSold,
+}
+
+// SaleStatusCode повертає код статусу або порожній рядок для невідомого статусу
+func SaleStatusCode(status string) string {
+	return saleStatusCodes[status]
+}
+
+// SaleStatusFromCode повертає статус для збереження за його кодом
+func SaleStatusFromCode(code string) (string, bool) {
+	for status, statusCode := range saleStatusCodes {
+		if statusCode == code {
+			return status, true
+		}
+	}
+
+	return "", false
+}
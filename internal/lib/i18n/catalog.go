@@ -0,0 +1,108 @@
+package i18n
+
+import (
+	"lots-service/internal/domain"
+	"lots-service/pkg/auth"
+)
+
+// Ключі повідомлень, які не є кодами помилок domain
+const (
+	MsgAccessRevoked    = "access_revoked"
+	MsgRouteNotFound    = "route_not_found"
+	MsgMethodNotAllowed = "method_not_allowed"
+	MsgLotCreated       = "lot_created"
+	MsgLotUpdated       = "lot_updated"
+	MsgLotDeleted       = "lot_deleted"
+	MsgLotBought        = "lot_bought"
+	MsgLotHidden        = "lot_hidden"
+	MsgLotShown         = "lot_shown"
+	MsgLotMarkedSold    = "lot_marked_sold"
+	MsgInvalidLogLevel  = "invalid_log_level"
+)
+
+var catalog = map[Locale]map[string]string{
+	Ukrainian: {
+		domain.CodeInternal:         "Помилка на сервері",
+		domain.CodeInvalidRequest:   "Некоректний запит",
+		domain.CodeInvalidLotID:     "Некоректний ID лота",
+		domain.CodeInvalidLot:       "Помилка валідації лота",
+		domain.CodeLotNotFound:      "Лот не знайдено",
+		domain.CodeNotLotOwner:      "Лот належить іншому користувачу",
+		domain.CodeImageNotOwned:    "Зображення не належить лоту",
+		domain.CodeLotAlreadySold:   "Лот вже продано",
+		domain.CodeInvalidUpload:    "Некоректні завантажені зображення",
+		domain.CodeUploadsDisabled:  "Пряме завантаження зображень вимкнено",
+		domain.CodeAlreadyExists:    "Запис вже існує",
+		domain.CodeInvalidReference: "Посилання на неіснуючий запис",
+		domain.CodeConcurrentUpdate: "Конфлікт одночасного оновлення, повторіть запит",
+		domain.CodeRequestTooLarge:  "Завеликий запит",
+		domain.CodeImageAlreadyUsed: "Зображення вже використано в іншому лоті",
+		domain.CodeTimeout:          "Сервер не встиг обробити запит, повторіть пізніше",
+
+		domain.SaleStatusCodeForSale: "Продається",
+		domain.SaleStatusCodeSold:    "Продано",
+
+		auth.CodeMissingToken:    "Не авторизовано",
+		auth.CodeForbidden:       "Доступ заборонено",
+		auth.CodeMissingAPIKey:   "Не передано API-ключ",
+		auth.CodeInvalidAPIKey:   "Недійсний API-ключ",
+		auth.CodeInvalidToken:    "Недійсний токен",
+		auth.CodeMalformedToken:  "Некоректний токен",
+		auth.CodeTokenExpired:    "Термін дії токена минув",
+		auth.CodeTokenRevoked:    "Токен відкликано",
+		auth.CodeAuthUnavailable: "Сервіс авторизації тимчасово недоступний",
+		MsgAccessRevoked:         "Доступ відкликано",
+		MsgRouteNotFound:         "Маршрут не знайдено",
+		MsgMethodNotAllowed:      "Заборонений метод",
+		MsgLotCreated:            "Лот створено",
+		MsgLotUpdated:            "Лот оновлено",
+		MsgLotDeleted:            "Лот видалено",
+		MsgLotBought:             "Лот успішно куплено",
+		MsgLotHidden:             "Лот приховано",
+		MsgLotShown:              "Лот знову показується",
+		MsgLotMarkedSold:         "Лот позначено проданим",
+		MsgInvalidLogLevel:       "Рівень логування має бути debug, info, warn або error",
+	},
+	English: {
+		domain.CodeInternal:         "Internal server error",
+		domain.CodeInvalidRequest:   "Invalid request",
+		domain.CodeInvalidLotID:     "Invalid lot ID",
+		domain.CodeInvalidLot:       "Lot validation failed",
+		domain.CodeLotNotFound:      "Lot not found",
+		domain.CodeNotLotOwner:      "Lot belongs to another user",
+		domain.CodeImageNotOwned:    "Image does not belong to the lot",
+		domain.CodeLotAlreadySold:   "Lot is already sold",
+		domain.CodeInvalidUpload:    "Invalid uploaded images",
+		domain.CodeUploadsDisabled:  "Direct image uploads are disabled",
+		domain.CodeAlreadyExists:    "Record already exists",
+		domain.CodeInvalidReference: "Reference to a nonexistent record",
+		domain.CodeConcurrentUpdate: "Concurrent update conflict, retry the request",
+		domain.CodeRequestTooLarge:  "Request body is too large",
+		domain.CodeImageAlreadyUsed: "Image is already used by another lot",
+		domain.CodeTimeout:          "The request timed out, please try again later",
+
+		domain.SaleStatusCodeForSale: "For sale",
+		domain.SaleStatusCodeSold:    "Sold",
+
+		auth.CodeMissingToken:    "Unauthorized",
+		auth.CodeForbidden:       "Access denied",
+		auth.CodeMissingAPIKey:   "API key is missing",
+		auth.CodeInvalidAPIKey:   "Invalid API key",
+		auth.CodeInvalidToken:    "Invalid token",
+		auth.CodeMalformedToken:  "Malformed token",
+		auth.CodeTokenExpired:    "Token has expired",
+		auth.CodeTokenRevoked:    "Token has been revoked",
+		auth.CodeAuthUnavailable: "Authorization service is temporarily unavailable",
+		MsgAccessRevoked:         "Access revoked",
+		MsgRouteNotFound:         "Route not found",
+		MsgMethodNotAllowed:      "Method not allowed",
+		MsgLotCreated:            "Lot created",
+		MsgLotUpdated:            "Lot updated",
+		MsgLotDeleted:            "Lot deleted",
+		MsgLotBought:             "Lot purchased successfully",
+		MsgLotHidden:             "Lot hidden",
+		MsgLotShown:              "Lot is visible again",
+		MsgLotMarkedSold:         "Lot marked as sold",
+		MsgInvalidLogLevel:       "Log level must be debug, info, warn or error",
+	},
+}
@@ -0,0 +1,107 @@
+package i18n
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Locale string
+
+const (
+	Ukrainian Locale = "uk"
+	English   Locale = "en"
+
+	// DefaultLocale використовується, якщо клієнт не вказав підтримувану мову
+	DefaultLocale = Ukrainian
+)
+
+// FromRequest обирає мову відповіді за заголовком Accept-Language
+func FromRequest(r *http.Request) Locale {
+	if r == nil {
+		return DefaultLocale
+	}
+
+	return ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// ParseAcceptLanguage повертає підтримувану мову з найбільшою вагою q,
+// наприклад "en-US,en;q=0.9,uk;q=0.8" -> en. Регіон ігнорується.
+func ParseAcceptLanguage(header string) Locale {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{tag: strings.ToLower(tag), q: q})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	for _, c := range candidates {
+		if c.tag == "*" {
+			return DefaultLocale
+		}
+
+		primary, _, _ := strings.Cut(c.tag, "-")
+		if _, ok := catalog[Locale(primary)]; ok {
+			return Locale(primary)
+		}
+	}
+
+	return DefaultLocale
+}
+
+// Lookup шукає повідомлення за ключем у вказаній мові, а потім у мові за замовчуванням
+func Lookup(locale Locale, key string) (string, bool) {
+	if msg, ok := catalog[locale][key]; ok {
+		return msg, true
+	}
+
+	msg, ok := catalog[DefaultLocale][key]
+	return msg, ok
+}
+
+// Message повертає локалізоване повідомлення або сам ключ, якщо перекладу немає
+func Message(locale Locale, key string) string {
+	if msg, ok := Lookup(locale, key); ok {
+		return msg
+	}
+
+	return key
+}
+
+// KeyOf шукає ключ за перекладом у будь-якій мові, наприклад "Sold" -> sold
+func KeyOf(message string) (string, bool) {
+	for _, messages := range catalog {
+		for key, msg := range messages {
+			if strings.EqualFold(msg, message) {
+				return key, true
+			}
+		}
+	}
+
+	return "", false
+}
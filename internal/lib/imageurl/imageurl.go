@@ -0,0 +1,75 @@
+package imageurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Builder struct {
+	baseURL string
+	secret  []byte
+	ttl     time.Duration
+}
+
+// NewBuilder створює генератор посилань на зображення відносно baseURL.
+// Без secret підписані посилання вимкнені і всі зображення віддаються
+// звичайними URL.
+func NewBuilder(baseURL string, secret []byte, ttl time.Duration) *Builder {
+	return &Builder{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  secret,
+		ttl:     ttl,
+	}
+}
+
+func (b *Builder) URL(name string) string {
+	return b.baseURL + "/" + url.PathEscape(name)
+}
+
+// SignedURL додає до посилання час завершення дії та HMAC-SHA256 підпис,
+// який перевіряє сервіс сховища
+func (b *Builder) SignedURL(name string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", Signature(b.secret, name+":"+expires))
+
+	return b.URL(name) + "?" + query.Encode()
+}
+
+// MustSign повідомляє, чи потрібно підписувати зображення лота. Приховані
+// лоти бачить лише власник, тож їх зображення віддаються тимчасовими посиланнями.
+func (b *Builder) MustSign(hidden bool) bool {
+	return len(b.secret) > 0 && hidden
+}
+
+func (b *Builder) URLs(names []string, hidden bool) []string {
+	urls := make([]string, 0, len(names))
+
+	if b.MustSign(hidden) {
+		expiresAt := time.Now().Add(b.ttl)
+		for _, name := range names {
+			urls = append(urls, b.SignedURL(name, expiresAt))
+		}
+		return urls
+	}
+
+	for _, name := range names {
+		urls = append(urls, b.URL(name))
+	}
+
+	return urls
+}
+
+func Signature(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,90 @@
+package imageurl
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSessionToken = errors.New("недійсний токен сесії завантаження")
+
+type UploadSession struct {
+	UserID    int      `json:"user_id"`
+	ImageIDs  []string `json:"image_ids"`
+	ExpiresAt int64    `json:"expires_at"`
+}
+
+// UploadSigner видає підписані посилання для прямого завантаження в storage
+// і токени сесій, за якими лот потім підтверджує завантажені зображення.
+// Сесії не зберігаються на сервері: вся інформація міститься в підписаному токені.
+type UploadSigner struct {
+	storageURL string
+	secret     []byte
+	ttl        time.Duration
+}
+
+func NewUploadSigner(storageURL string, secret []byte, ttl time.Duration) *UploadSigner {
+	return &UploadSigner{
+		storageURL: strings.TrimRight(storageURL, "/"),
+		secret:     secret,
+		ttl:        ttl,
+	}
+}
+
+func (u *UploadSigner) Enabled() bool {
+	return len(u.secret) > 0
+}
+
+func (u *UploadSigner) TTL() time.Duration {
+	return u.ttl
+}
+
+func (u *UploadSigner) UploadURL(imageID string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", Signature(u.secret, "upload:"+imageID+":"+expires))
+
+	return fmt.Sprintf("%s/api/storage/upload_presigned/%s?%s", u.storageURL, url.PathEscape(imageID), query.Encode())
+}
+
+func (u *UploadSigner) SessionToken(session UploadSession) (string, error) {
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + Signature(u.secret, encoded), nil
+}
+
+func (u *UploadSigner) ParseSessionToken(token string, now time.Time) (*UploadSession, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(Signature(u.secret, encoded))) {
+		return nil, ErrInvalidSessionToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidSessionToken
+	}
+
+	var session UploadSession
+	if err := json.Unmarshal(payload, &session); err != nil {
+		return nil, ErrInvalidSessionToken
+	}
+
+	if now.Unix() > session.ExpiresAt {
+		return nil, fmt.Errorf("%w: термін дії минув", ErrInvalidSessionToken)
+	}
+
+	return &session, nil
+}
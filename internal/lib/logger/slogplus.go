@@ -3,6 +3,7 @@ package logger
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	stdLog "log"
 	"log/slog"
@@ -10,27 +11,80 @@ import (
 	"github.com/fatih/color"
 )
 
+// Формати виводу логів
+const (
+	// FormatPretty — кольоровий вивід з відступами для розробки
+	FormatPretty = "pretty"
+	// FormatJSON — один JSON-рядок на запис для збору логів у проді
+	FormatJSON = "json"
+)
+
+// level спільний для всіх обробників, тож його можна змінювати під час роботи
+var level slog.LevelVar
+
 type PlusHandlerOptions struct {
 	SlogOpts *slog.HandlerOptions
 }
 
+// PlusHandler виводить записи у зручному для читання вигляді. Атрибути з
+// WithAttrs накопичуються, а WithGroup вкладає наступні атрибути в групу,
+// як це робить slog.JSONHandler.
 type PlusHandler struct {
 	slog.Handler
-	log   *stdLog.Logger
-	attrs []slog.Attr
+	log    *stdLog.Logger
+	attrs  []groupedAttr
+	groups []string
+}
+
+// groupedAttr — атрибут з WithAttrs разом з групами, відкритими на той момент
+type groupedAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// InitGlobalLogger налаштовує slog.Default з форматом і рівнем з конфігу
+func InitGlobalLogger(writer io.Writer, format, levelName string) error {
+	lvl, err := ParseLevel(levelName)
+	if err != nil {
+		return err
+	}
+	level.Set(lvl)
+
+	opts := &slog.HandlerOptions{Level: &level}
+
+	var handler slog.Handler
+	switch format {
+	case "", FormatPretty:
+		handler = PlusHandlerOptions{SlogOpts: opts}.NewPlusHandler(writer)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(writer, opts)
+	default:
+		return fmt.Errorf("unknown log format: %q", format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+
+	return nil
 }
 
-func InitGlobalLogger(writer io.Writer, level slog.Level) {
-	opts := PlusHandlerOptions{
-		SlogOpts: &slog.HandlerOptions{
-			Level: level,
-		},
+// ParseLevel розбирає debug, info, warn, error (а також INFO+2 тощо)
+func ParseLevel(name string) (slog.Level, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("unknown log level: %q", name)
 	}
 
-	handler := opts.NewPlusHandler(writer)
-	logger := slog.New(handler)
+	return lvl, nil
+}
+
+// Level повертає поточний рівень глобального логера
+func Level() slog.Level {
+	return level.Level()
+}
 
-	slog.SetDefault(logger)
+// SetLevel змінює рівень глобального логера без перезапуску
+func SetLevel(lvl slog.Level) {
+	level.Set(lvl)
 }
 
 func (opts PlusHandlerOptions) NewPlusHandler(out io.Writer) *PlusHandler {
@@ -56,18 +110,18 @@ func (h *PlusHandler) Handle(_ context.Context, rec slog.Record) error {
 		level = color.RedString(level)
 	}
 
-	fields := make(map[string]any, rec.NumAttrs())
+	fields := make(map[string]any, len(h.attrs)+rec.NumAttrs())
+
+	for _, ga := range h.attrs {
+		addAttr(fields, ga.groups, ga.attr)
+	}
 
 	rec.Attrs(func(a slog.Attr) bool {
-		fields[a.Key] = a.Value.Any()
+		addAttr(fields, h.groups, a)
 
 		return true
 	})
 
-	for _, a := range h.attrs {
-		fields[a.Key] = a.Value.Any()
-	}
-
 	var b []byte
 	var err error
 
@@ -93,16 +147,72 @@ func (h *PlusHandler) Handle(_ context.Context, rec slog.Record) error {
 }
 
 func (h *PlusHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &PlusHandler{
-		Handler: h.Handler,
-		log:     h.log,
-		attrs:   attrs,
+	if len(attrs) == 0 {
+		return h
 	}
+
+	h2 := h.clone()
+	for _, a := range attrs {
+		h2.attrs = append(h2.attrs, groupedAttr{groups: h.groups, attr: a})
+	}
+
+	return h2
 }
 
 func (h *PlusHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := h.clone()
+	h2.Handler = h.Handler.WithGroup(name)
+	h2.groups = append(h2.groups, name)
+
+	return h2
+}
+
+// clone копіює зрізи, щоб похідні обробники не перезаписували атрибути один одного
+func (h *PlusHandler) clone() *PlusHandler {
 	return &PlusHandler{
-		Handler: h.Handler.WithGroup(name),
+		Handler: h.Handler,
 		log:     h.log,
+		attrs:   append([]groupedAttr(nil), h.attrs...),
+		groups:  append([]string(nil), h.groups...),
+	}
+}
+
+// addAttr кладе атрибут у вкладену мапу за шляхом груп за правилами slog:
+// порожні атрибути пропускаються, група без ключа розгортається на поточний рівень
+func addAttr(fields map[string]any, groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
 	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		members := a.Value.Group()
+		if len(members) == 0 {
+			return
+		}
+
+		path := groups
+		if a.Key != "" {
+			path = append(append([]string(nil), groups...), a.Key)
+		}
+		for _, member := range members {
+			addAttr(fields, path, member)
+		}
+		return
+	}
+
+	for _, group := range groups {
+		nested, ok := fields[group].(map[string]any)
+		if !ok {
+			nested = make(map[string]any)
+			fields[group] = nested
+		}
+		fields = nested
+	}
+
+	fields[a.Key] = a.Value.Any()
 }
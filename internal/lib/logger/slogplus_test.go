@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+type userValue struct{ id int }
+
+func (u userValue) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("id", u.id), slog.String("kind", "user"))
+}
+
+// plusFields повертає атрибути, які PlusHandler вивів JSON-блоком після повідомлення
+func plusFields(t *testing.T, out string) map[string]any {
+	t.Helper()
+
+	fields := map[string]any{}
+	start := strings.Index(out, "{")
+	if start < 0 {
+		return fields
+	}
+	if err := json.Unmarshal([]byte(out[start:]), &fields); err != nil {
+		t.Fatalf("PlusHandler output is not JSON: %v\n%s", err, out)
+	}
+
+	return fields
+}
+
+// jsonFields повертає атрибути запису slog.JSONHandler без службових ключів
+func jsonFields(t *testing.T, out string) map[string]any {
+	t.Helper()
+
+	fields := map[string]any{}
+	if err := json.Unmarshal([]byte(out), &fields); err != nil {
+		t.Fatalf("JSONHandler output is not JSON: %v\n%s", err, out)
+	}
+	for _, key := range []string{slog.TimeKey, slog.LevelKey, slog.MessageKey} {
+		delete(fields, key)
+	}
+
+	return fields
+}
+
+func TestPlusHandlerMatchesJSONHandler(t *testing.T) {
+	color.NoColor = true
+
+	tests := []struct {
+		name string
+		log  func(l *slog.Logger)
+	}{
+		{"plain attrs", func(l *slog.Logger) {
+			l.Info("m", "a", 1, "b", "x")
+		}},
+		{"WithAttrs chaining", func(l *slog.Logger) {
+			l.With("a", 1).With("b", 2).Info("m", "c", 3)
+		}},
+		{"derived loggers do not share attrs", func(l *slog.Logger) {
+			base := l.With("a", 1)
+			_ = base.With("leak", true)
+			base.With("b", 2).Info("m")
+		}},
+		{"WithGroup nesting", func(l *slog.Logger) {
+			l.WithGroup("g").With("a", 1).WithGroup("h").With("b", 2).Info("m", "c", 3)
+		}},
+		{"attrs before and after group", func(l *slog.Logger) {
+			l.With("a", 1).WithGroup("g").Info("m", "b", 2)
+		}},
+		{"group attr inside WithGroup", func(l *slog.Logger) {
+			l.WithGroup("g").Info("m", slog.Group("req", slog.String("method", "GET"), slog.Int("status", 200)))
+		}},
+		{"inline group", func(l *slog.Logger) {
+			l.Info("m", slog.Group("", slog.Int("a", 1), slog.Int("b", 2)))
+		}},
+		{"empty group", func(l *slog.Logger) {
+			l.Info("m", "a", 1, slog.Group("empty"))
+		}},
+		{"empty WithGroup", func(l *slog.Logger) {
+			l.WithGroup("g").Info("m")
+		}},
+		{"empty attr", func(l *slog.Logger) {
+			l.Info("m", slog.Attr{}, "a", 1)
+		}},
+		{"LogValuer", func(l *slog.Logger) {
+			l.Info("m", "user", userValue{id: 7})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var plusOut, jsonOut bytes.Buffer
+			tt.log(slog.New(PlusHandlerOptions{SlogOpts: &slog.HandlerOptions{}}.NewPlusHandler(&plusOut)))
+			tt.log(slog.New(slog.NewJSONHandler(&jsonOut, nil)))
+
+			got, want := plusFields(t, plusOut.String()), jsonFields(t, jsonOut.String())
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("PlusHandler fields = %v, want %v (as JSONHandler)", got, want)
+			}
+		})
+	}
+}
+
+func TestPlusHandlerRespectsLevel(t *testing.T) {
+	color.NoColor = true
+
+	var out bytes.Buffer
+	opts := &slog.HandlerOptions{Level: slog.LevelWarn}
+	l := slog.New(PlusHandlerOptions{SlogOpts: opts}.NewPlusHandler(&out)).With("a", 1)
+
+	l.Info("hidden")
+	l.Warn("shown")
+
+	if strings.Contains(out.String(), "hidden") || !strings.Contains(out.String(), "WARN: shown") {
+		t.Fatalf("output = %q, want only the warning", out.String())
+	}
+}
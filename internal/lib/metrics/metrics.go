@@ -0,0 +1,117 @@
+// Package metrics описує метрики Prometheus сервісу. Мітки мають обмежену
+// кількість значень: шаблони маршрутів, імена методів, класи статусів.
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "lots"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route template, method and status.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route template, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of repository methods.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	storageRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "storage_requests_total",
+		Help:      "Storage service calls by operation and outcome (2xx, 4xx, 5xx, error).",
+	}, []string{"operation", "outcome"})
+
+	storageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_request_duration_seconds",
+		Help:      "Storage service call latency by operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	lotsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "lots_created_total",
+		Help:      "Lots created.",
+	})
+
+	lotsSold = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "lots_sold_total",
+		Help:      "Lots marked as sold by source (buyer, internal).",
+	}, []string{"source"})
+
+	lotLikes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "lot_likes_total",
+		Help:      "Lot likes and unlikes.",
+	}, []string{"action"})
+)
+
+// Джерела продажу лота для LotSold
+const (
+	SoldByBuyer    = "buyer"
+	SoldByInternal = "internal"
+)
+
+func ObserveHTTP(route, method string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	httpRequests.WithLabelValues(route, method, statusLabel).Inc()
+	httpDuration.WithLabelValues(route, method, statusLabel).Observe(duration.Seconds())
+}
+
+func ObserveQuery(method string, duration time.Duration) {
+	dbQueryDuration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// ObserveStorage записує виклик storage; status 0 означає помилку транспорту
+func ObserveStorage(operation string, status int, duration time.Duration) {
+	outcome := "error"
+	if status > 0 {
+		outcome = strconv.Itoa(status/100) + "xx"
+	}
+
+	storageRequests.WithLabelValues(operation, outcome).Inc()
+	storageDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+func LotCreated() {
+	lotsCreated.Inc()
+}
+
+func LotSold(source string) {
+	lotsSold.WithLabelValues(source).Inc()
+}
+
+func LotLiked(liked bool) {
+	action := "unlike"
+	if liked {
+		action = "like"
+	}
+
+	lotLikes.WithLabelValues(action).Inc()
+}
+
+// RegisterDB експортує статистику пулу з'єднань sql.DB
+func RegisterDB(db *sql.DB, dbName string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, dbName))
+}
@@ -0,0 +1,81 @@
+package responseHTTP
+
+import (
+	"errors"
+	"lots-service/internal/domain"
+	"lots-service/internal/lib/i18n"
+	"lots-service/internal/lib/logger"
+	"lots-service/internal/lib/tracing"
+	"lots-service/pkg/auth"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WriteError — єдине місце, де помилки сервісу перетворюються на HTTP-відповідь:
+// статус за видом domain.Error (або з auth.Error), стабільний код і повідомлення для користувача.
+// Повідомлення локалізується за Accept-Language.
+// Невідомі помилки стають 500 internal_error без розкриття подробиць.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	code := domain.CodeInternal
+	var fields map[string]string
+
+	var domainErr *domain.Error
+	var authErr *auth.Error
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		// Ліміт тіла з конфігу, а не помилка клієнтських даних
+		status = http.StatusRequestEntityTooLarge
+		code = domain.CodeRequestTooLarge
+	} else if errors.As(err, &authErr) {
+		status = authErr.Status
+		code = authErr.Code
+		// Відмова з обробника (без auth-middleware) теж має Bearer-challenge
+		if challenge := authErr.Challenge(); status == http.StatusUnauthorized && challenge != "" {
+			w.Header().Set("WWW-Authenticate", challenge)
+		}
+	} else if errors.As(err, &domainErr) {
+		status = statusFor(domainErr.Kind)
+		code = domainErr.Code
+		fields = domainErr.Fields
+	}
+
+	if status >= http.StatusInternalServerError {
+		logger.FromContext(r.Context()).Error("Помилка обробки запиту", "code", code, "err", err.Error())
+		tracing.Fail(trace.SpanFromContext(r.Context()), err)
+	}
+
+	locale := i18n.FromRequest(r)
+	writeJSONError(w, locale, status, ErrorResponse{
+		Message:   message(locale, code),
+		Code:      status,
+		ErrorCode: code,
+		Fields:    fields,
+	})
+}
+
+func statusFor(kind error) int {
+	switch kind {
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrValidation:
+		return http.StatusUnprocessableEntity
+	case domain.ErrUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func message(locale i18n.Locale, code string) string {
+	if msg, ok := i18n.Lookup(locale, code); ok {
+		return msg
+	}
+
+	return i18n.Message(locale, domain.CodeInternal)
+}
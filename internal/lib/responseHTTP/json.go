@@ -3,20 +3,29 @@ package responseHTTP
 import (
 	"encoding/json"
 	"log/slog"
+	"lots-service/internal/lib/i18n"
 	"net/http"
 )
 
 type ErrorResponse struct {
-	Message string `json:"message"`
-	Code    int    `json:"code,omitempty"`
+	Message   string            `json:"message"`
+	Code      int               `json:"code,omitempty"`
+	ErrorCode string            `json:"error_code,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
 }
 
-func JSONError(w http.ResponseWriter, code int, errMessage string) {
+// JSONError відповідає помилкою з повідомленням з каталогу i18n за ключем messageKey
+func JSONError(w http.ResponseWriter, r *http.Request, code int, messageKey string) {
+	locale := i18n.FromRequest(r)
+	writeJSONError(w, locale, code, ErrorResponse{Message: i18n.Message(locale, messageKey), Code: code})
+}
+
+func writeJSONError(w http.ResponseWriter, locale i18n.Locale, code int, resp ErrorResponse) {
+	setLanguage(w, locale)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.Header().Set("X-Content-Type-Options", "nosniff")
 	w.WriteHeader(code)
 
-	resp := ErrorResponse{Message: errMessage, Code: code}
 	err := json.NewEncoder(w).Encode(resp)
 	if err != nil {
 		slog.Debug("Помилка у кодуванні JSONError:", "err", err.Error())
@@ -32,12 +41,19 @@ func JSONResp(w http.ResponseWriter, code int, payload any) {
 	}
 }
 
-func JSONRespMessage(w http.ResponseWriter, code int, message string) {
+func JSONRespMessage(w http.ResponseWriter, r *http.Request, code int, messageKey string) {
+	locale := i18n.FromRequest(r)
+	setLanguage(w, locale)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(code)
-	resp := ErrorResponse{Message: message, Code: code}
+	resp := ErrorResponse{Message: i18n.Message(locale, messageKey), Code: code}
 	err := json.NewEncoder(w).Encode(resp)
 	if err != nil {
 		slog.Debug("Помилка у кодуванні JSONRespMessage:", "err", err.Error())
 	}
 }
+
+func setLanguage(w http.ResponseWriter, locale i18n.Locale) {
+	w.Header().Set("Content-Language", string(locale))
+	w.Header().Add("Vary", "Accept-Language")
+}
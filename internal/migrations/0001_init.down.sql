@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS liked_lots;
+DROP TABLE IF EXISTS sell_lots;
+DROP TABLE IF EXISTS cars;
+DROP TABLE IF EXISTS models;
+DROP TABLE IF EXISTS brands;
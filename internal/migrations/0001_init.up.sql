@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS brands (
+    brand_id   SERIAL PRIMARY KEY,
+    brand_name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS models (
+    model_id   SERIAL PRIMARY KEY,
+    brand_id   INTEGER NOT NULL REFERENCES brands (brand_id),
+    model_name TEXT NOT NULL,
+    UNIQUE (brand_id, model_name)
+);
+
+CREATE TABLE IF NOT EXISTS cars (
+    car_id       SERIAL PRIMARY KEY,
+    brand_id     INTEGER NOT NULL REFERENCES brands (brand_id),
+    model_id     INTEGER NOT NULL REFERENCES models (model_id),
+    made_year    INTEGER NOT NULL,
+    engine_type  TEXT NOT NULL DEFAULT '',
+    transmission TEXT NOT NULL DEFAULT '',
+    wheel_drive  TEXT NOT NULL DEFAULT '',
+    description  TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS sell_lots (
+    lot_id       SERIAL PRIMARY KEY,
+    seller_id    INTEGER NOT NULL,
+    car_id       INTEGER NOT NULL REFERENCES cars (car_id),
+    postdate     DATE NOT NULL DEFAULT CURRENT_DATE,
+    sale_price   INTEGER NOT NULL,
+    sale_status  TEXT NOT NULL DEFAULT 'Продається',
+    vin_code     TEXT NOT NULL DEFAULT '',
+    mileage      INTEGER NOT NULL DEFAULT 0,
+    color        TEXT NOT NULL DEFAULT '',
+    description  TEXT NOT NULL DEFAULT '',
+    images_paths TEXT[] NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS sell_lots_seller_id_idx ON sell_lots (seller_id);
+CREATE INDEX IF NOT EXISTS sell_lots_postdate_idx ON sell_lots (postdate DESC);
+
+CREATE TABLE IF NOT EXISTS liked_lots (
+    user_id INTEGER NOT NULL,
+    lot_id  INTEGER NOT NULL REFERENCES sell_lots (lot_id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, lot_id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS admin_actions;
+
+ALTER TABLE sell_lots DROP COLUMN IF EXISTS is_hidden;
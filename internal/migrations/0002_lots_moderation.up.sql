@@ -0,0 +1,13 @@
+ALTER TABLE sell_lots ADD COLUMN IF NOT EXISTS is_hidden BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- lot_id без зовнішнього ключа: журнал зберігається і після видалення лота
+CREATE TABLE IF NOT EXISTS admin_actions (
+    action_id  BIGSERIAL PRIMARY KEY,
+    admin_id   INTEGER NOT NULL,
+    lot_id     INTEGER NOT NULL,
+    action     TEXT NOT NULL,
+    reason     TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS admin_actions_lot_id_idx ON admin_actions (lot_id);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS consumed_upload_images (
+    image_id    TEXT PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    consumed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
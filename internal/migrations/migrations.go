@@ -0,0 +1,8 @@
+// Package migrations містить версійовані SQL-міграції схеми БД, вбудовані в бінарник.
+// Файли називаються <версія>_<назва>.up.sql та <версія>_<назва>.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
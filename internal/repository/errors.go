@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lots-service/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// Коди помилок PostgreSQL, які мають значення для клієнта
+const (
+	uniqueViolation      pq.ErrorCode = "23505"
+	foreignKeyViolation  pq.ErrorCode = "23503"
+	serializationFailure pq.ErrorCode = "40001"
+	deadlockDetected     pq.ErrorCode = "40P01"
+	queryCanceled        pq.ErrorCode = "57014"
+)
+
+// dbError перекладає помилки драйвера у помилки предметної області, щоб
+// сервіс і хендлери не залежали від database/sql та lib/pq.
+// notFound повертається замість sql.ErrNoRows; якщо він nil, ErrNoRows
+// лишається внутрішньою помилкою. Інші помилки повертаються без змін.
+func dbError(err error, notFound *domain.Error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		if notFound != nil {
+			return notFound.Wrap(err)
+		}
+		return err
+	}
+
+	// Запит перервано таймаутом з конфігу або statement_timeout. Скасування
+	// клієнтом (57014 або context.Canceled, якщо пул відкинув з'єднання)
+	// відповідає так само, хоча тоді відповідь уже нікому не потрібна.
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return domain.NewUnavailable(domain.CodeTimeout, "").Wrap(err)
+	}
+
+	switch pqCode(err) {
+	case queryCanceled:
+		return domain.NewUnavailable(domain.CodeTimeout, "").Wrap(err)
+	case uniqueViolation:
+		return domain.NewConflict(domain.CodeAlreadyExists, constraintOf(err)).Wrap(err)
+	case foreignKeyViolation:
+		return domain.NewValidation(domain.CodeInvalidReference, nil).Wrap(err)
+	case serializationFailure, deadlockDetected:
+		return domain.NewConflict(domain.CodeConcurrentUpdate, constraintOf(err)).Wrap(err)
+	}
+
+	return err
+}
+
+func pqCode(err error) pq.ErrorCode {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code
+	}
+
+	return ""
+}
+
+func constraintOf(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint
+	}
+
+	return ""
+}
+
+func lotNotFound(lotID int) *domain.Error {
+	return domain.NewNotFound(domain.CodeLotNotFound, fmt.Sprintf("лот %d", lotID))
+}
+
+// checkAffected повертає notFound, якщо запит не змінив жодного рядка
+func checkAffected(result sql.Result, notFound *domain.Error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lots-service/internal/domain"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestDBError(t *testing.T) {
+	notFound := lotNotFound(1)
+
+	tests := []struct {
+		name     string
+		err      error
+		notFound *domain.Error
+		wantKind error
+		wantCode string
+	}{
+		{"no rows", sql.ErrNoRows, notFound, domain.ErrNotFound, domain.CodeLotNotFound},
+		{"unique", &pq.Error{Code: uniqueViolation}, nil, domain.ErrConflict, domain.CodeAlreadyExists},
+		{"foreign key", &pq.Error{Code: foreignKeyViolation}, nil, domain.ErrValidation, domain.CodeInvalidReference},
+		{"serialization", &pq.Error{Code: serializationFailure}, nil, domain.ErrConflict, domain.CodeConcurrentUpdate},
+		{"query timeout", fmt.Errorf("exec: %w", context.DeadlineExceeded), nil, domain.ErrUnavailable, domain.CodeTimeout},
+		{"client canceled", fmt.Errorf("query: %w", context.Canceled), nil, domain.ErrUnavailable, domain.CodeTimeout},
+		{"statement canceled", &pq.Error{Code: queryCanceled}, nil, domain.ErrUnavailable, domain.CodeTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dbError(tt.err, tt.notFound)
+
+			var domainErr *domain.Error
+			if !errors.As(err, &domainErr) || !errors.Is(err, tt.wantKind) || domainErr.Code != tt.wantCode {
+				t.Fatalf("dbError = %v, want %v %s", err, tt.wantKind, tt.wantCode)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("dbError lost the cause %v", tt.err)
+			}
+		})
+	}
+
+	if err := dbError(sql.ErrNoRows, nil); err != sql.ErrNoRows {
+		t.Fatalf("dbError without notFound = %v, want sql.ErrNoRows", err)
+	}
+}
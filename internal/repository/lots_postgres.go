@@ -4,45 +4,54 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log/slog"
 	"lots-service/internal/domain"
+	"lots-service/internal/lib/logger"
 	"strings"
+	"time"
 
 	"github.com/lib/pq"
 )
 
 type PostgresLotsRepo struct {
-	db *sql.DB
+	db           *sql.DB
+	queryTimeout time.Duration
 }
 
-func NewPostgresLotsRepo(db *sql.DB) *PostgresLotsRepo {
-	return &PostgresLotsRepo{db: db}
+// queryTimeout обмежує тривалість кожного запиту, 0 — без обмеження
+func NewPostgresLotsRepo(db *sql.DB, queryTimeout time.Duration) *PostgresLotsRepo {
+	return &PostgresLotsRepo{db: db, queryTimeout: queryTimeout}
 }
 
-func (r *PostgresLotsRepo) GetLotsCount() (int, error) {
-	lotsCount := 0
-	err := r.db.QueryRow("SELECT COUNT(*) FROM sell_lots").Scan(&lotsCount)
+func (r *PostgresLotsRepo) begin(ctx context.Context, method string) (context.Context, func()) {
+	return beginQuery(ctx, r.queryTimeout, "PostgresLotsRepo."+method)
+}
 
+func (r *PostgresLotsRepo) GetLotsCount(ctx context.Context) (int, error) {
+	ctx, done := r.begin(ctx, "GetLotsCount")
+	defer done()
+
+	// COUNT(*) завжди повертає рядок, тож sql.ErrNoRows тут неможливий
+	lotsCount := 0
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sell_lots WHERE NOT is_hidden").Scan(&lotsCount)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			slog.Debug("Кількість лотів не знайдено в БД", "err", err.Error())
-			return 0, err
-		}
-		slog.Debug("Помилка при скануванні даних", "err", err.Error())
-		return 0, err
+		logger.FromContext(ctx).Debug("Помилка при скануванні даних", "err", err.Error())
+		return 0, dbError(err, nil)
 	}
 
 	return lotsCount, nil
 }
 
-func (r *PostgresLotsRepo) GetLotsByParamsCount(brand, model, minPrice, maxPrice, minYear, maxYear string) (int, error) {
+func (r *PostgresLotsRepo) GetLotsByParamsCount(ctx context.Context, brand, model, minPrice, maxPrice, minYear, maxYear string) (int, error) {
+	ctx, done := r.begin(ctx, "GetLotsByParamsCount")
+	defer done()
+
 	baseQuery := `
 	SELECT COUNT(*)
 	FROM sell_lots sl
 	JOIN cars c ON sl.car_id = c.car_id
 	JOIN brands b ON c.brand_id = b.brand_id
 	JOIN models m ON c.model_id = m.model_id
-	WHERE 1=1 
+	WHERE NOT sl.is_hidden
 	`
 
 	var args []any
@@ -77,17 +86,19 @@ func (r *PostgresLotsRepo) GetLotsByParamsCount(brand, model, minPrice, maxPrice
 	fullQuery := baseQuery + strings.Join(conditions, "\n")
 
 	var lotsCount int
-	err := r.db.QueryRow(fullQuery, args...).Scan(&lotsCount)
+	err := r.db.QueryRowContext(ctx, fullQuery, args...).Scan(&lotsCount)
 	if err != nil {
-		slog.Debug("Помилка отримання кількості лотів", "err", err.Error())
-		return 0, err
+		logger.FromContext(ctx).Debug("Помилка отримання кількості лотів", "err", err.Error())
+		return 0, dbError(err, nil)
 	}
 
 	return lotsCount, nil
 }
 
-func (r *PostgresLotsRepo) GetLotsByParams(userID int, page, limit int,
+func (r *PostgresLotsRepo) GetLotsByParams(ctx context.Context, userID int, page, limit int,
 	brand, model, minPrice, maxPrice, minYear, maxYear string) (*[]domain.Lot, int, error) {
+	ctx, done := r.begin(ctx, "GetLotsByParams")
+	defer done()
 
 	baseQuery := `
 	SELECT 
@@ -124,7 +135,7 @@ func (r *PostgresLotsRepo) GetLotsByParams(userID int, page, limit int,
 	JOIN cars c ON sl.car_id = c.car_id
 	JOIN brands b ON c.brand_id = b.brand_id
 	JOIN models m ON c.model_id = m.model_id
-	WHERE 1=1`
+	WHERE NOT sl.is_hidden`
 
 	if brand != "" {
 		addCondition("b.brand_name =", brand)
@@ -158,10 +169,10 @@ func (r *PostgresLotsRepo) GetLotsByParams(userID int, page, limit int,
 		fmt.Sprintf("\nORDER BY sl.postdate DESC LIMIT $%d OFFSET $%d", argCounter, argCounter+1)
 	args = append(args, limit, offset)
 
-	queryRows, err := r.db.Query(query, args...)
+	queryRows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		slog.Debug("Лоти не знайдені", "err", err.Error())
-		return nil, 0, err
+		logger.FromContext(ctx).Debug("Лоти не знайдені", "err", err.Error())
+		return nil, 0, dbError(err, nil)
 	}
 	defer queryRows.Close()
 
@@ -182,7 +193,7 @@ func (r *PostgresLotsRepo) GetLotsByParams(userID int, page, limit int,
 			&totalCount,
 		)
 		if err != nil {
-			slog.Debug("Помилка при скануванні", "err", err.Error())
+			logger.FromContext(ctx).Debug("Помилка при скануванні", "err", err.Error())
 			continue
 		}
 
@@ -194,18 +205,25 @@ func (r *PostgresLotsRepo) GetLotsByParams(userID int, page, limit int,
 
 		lots = append(lots, lot)
 	}
+	if err := queryRows.Err(); err != nil {
+		logger.FromContext(ctx).Debug("Помилка читання лотів", "err", err.Error())
+		return nil, 0, dbError(err, nil)
+	}
 
 	return &lots, totalCount, nil
 }
 
-func (r *PostgresLotsRepo) GetLotByID(userID, lotID int) (*domain.Lot, error) {
+func (r *PostgresLotsRepo) GetLotByID(ctx context.Context, userID, lotID int) (*domain.Lot, error) {
+	ctx, done := r.begin(ctx, "GetLotByID")
+	defer done()
+
 	query := `
 	SELECT 
   sl.lot_id, sl.seller_id, 
   sl.postdate, sl.sale_price, sl.sale_status, sl.vin_code,
 	sl.mileage, sl.color, sl.description, sl.images_paths,
   c.car_id, c.made_year, c.engine_type, c.transmission, c.wheel_drive, 
-	b.brand_name, b.brand_id, m.model_name, m.model_id, 
+	b.brand_name, b.brand_id, m.model_name, m.model_id, sl.is_hidden,
 	EXISTS (
 		SELECT 1 FROM liked_lots ll WHERE ll.user_id = $2 AND ll.lot_id = sl.lot_id
 	)
@@ -216,7 +234,7 @@ func (r *PostgresLotsRepo) GetLotByID(userID, lotID int) (*domain.Lot, error) {
 	WHERE sl.lot_id = $1;
 	`
 
-	row := r.db.QueryRow(query, lotID, userID)
+	row := r.db.QueryRowContext(ctx, query, lotID, userID)
 
 	var lot domain.Lot
 	var images pq.StringArray
@@ -226,17 +244,17 @@ func (r *PostgresLotsRepo) GetLotByID(userID, lotID int) (*domain.Lot, error) {
 		&lot.Car.Mileage, &lot.Car.Color, &lot.Description, &images,
 		&lot.Car.CarID, &lot.Car.MadeYear, &lot.Car.Engine,
 		&lot.Car.Transmission, &lot.Car.WheelDrive,
-		&lot.Car.Brand, &lot.Car.BrandID, &lot.Car.Model, &lot.Car.ModelID,
+		&lot.Car.Brand, &lot.Car.BrandID, &lot.Car.Model, &lot.Car.ModelID, &lot.IsHidden,
 		&lot.IsLiked,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			slog.Debug("Лот не знайдено в БД", "err", err.Error(), "LotID", lotID)
-			return nil, err
+			logger.FromContext(ctx).Debug("Лот не знайдено в БД", "err", err.Error(), "LotID", lotID)
+			return nil, lotNotFound(lotID).Wrap(err)
 		}
-		slog.Debug("Помилка при скануванні", "err", err.Error(), "LotID", lotID)
-		return nil, err
+		logger.FromContext(ctx).Debug("Помилка при скануванні", "err", err.Error(), "LotID", lotID)
+		return nil, dbError(err, nil)
 	}
 
 	if images != nil {
@@ -248,13 +266,16 @@ func (r *PostgresLotsRepo) GetLotByID(userID, lotID int) (*domain.Lot, error) {
 	return &lot, nil
 }
 
-func (r *PostgresLotsRepo) GetBrands() (*[]domain.Brand, error) {
+func (r *PostgresLotsRepo) GetBrands(ctx context.Context) (*[]domain.Brand, error) {
+	ctx, done := r.begin(ctx, "GetBrands")
+	defer done()
+
 	query := `SELECT brand_id, brand_name FROM brands`
 
-	queryRows, err := r.db.Query(query)
+	queryRows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
-		slog.Debug("Бренди не знайдені в БД", "err", err.Error())
-		return nil, err
+		logger.FromContext(ctx).Debug("Бренди не знайдені в БД", "err", err.Error())
+		return nil, dbError(err, nil)
 	}
 	defer queryRows.Close()
 
@@ -266,17 +287,20 @@ func (r *PostgresLotsRepo) GetBrands() (*[]domain.Brand, error) {
 			&brand.BrandID, &brand.BrandName,
 		)
 		if err != nil {
-			slog.Debug("Помилка при скануванні", "err", err.Error())
-			return nil, err
+			logger.FromContext(ctx).Debug("Помилка при скануванні", "err", err.Error())
+			return nil, dbError(err, nil)
 		}
 
 		brands = append(brands, brand)
 	}
 
-	return &brands, err
+	return &brands, dbError(queryRows.Err(), nil)
 }
 
-func (r *PostgresLotsRepo) GetModels(brandName string) (*[]domain.Model, error) {
+func (r *PostgresLotsRepo) GetModels(ctx context.Context, brandName string) (*[]domain.Model, error) {
+	ctx, done := r.begin(ctx, "GetModels")
+	defer done()
+
 	query := `
 	SELECT m.model_id, m.brand_id, m.model_name
   FROM models m
@@ -287,10 +311,10 @@ func (r *PostgresLotsRepo) GetModels(brandName string) (*[]domain.Model, error)
 		query += ` WHERE b.brand_name ILIKE $1`
 	}
 
-	queryRows, err := r.db.Query(query, brandName)
+	queryRows, err := r.db.QueryContext(ctx, query, brandName)
 	if err != nil {
-		slog.Debug("Моделі не знайдені", "err", err.Error())
-		return nil, err
+		logger.FromContext(ctx).Debug("Моделі не знайдені", "err", err.Error())
+		return nil, dbError(err, nil)
 	}
 	defer queryRows.Close()
 
@@ -302,23 +326,26 @@ func (r *PostgresLotsRepo) GetModels(brandName string) (*[]domain.Model, error)
 			&model.ModelID, &model.BrandID, &model.ModelName,
 		)
 		if err != nil {
-			slog.Debug("Помилка при скануванні", "err", err.Error())
-			return nil, err
+			logger.FromContext(ctx).Debug("Помилка при скануванні", "err", err.Error())
+			return nil, dbError(err, nil)
 		}
 
 		models = append(models, model)
 	}
 
-	return &models, err
+	return &models, dbError(queryRows.Err(), nil)
 }
 
-func (r *PostgresLotsRepo) GetUserPostedLots(userID int) (*[]domain.Lot, error) {
+func (r *PostgresLotsRepo) GetUserPostedLots(ctx context.Context, userID int) (*[]domain.Lot, error) {
+	ctx, done := r.begin(ctx, "GetUserPostedLots")
+	defer done()
+
 	query := `
 	SELECT sl.lot_id, sl.seller_id, 
   sl.postdate, sl.sale_price, sl.sale_status, sl.vin_code, 
 	sl.mileage, sl.color, sl.description, sl.images_paths,
   c.car_id, c.made_year, c.engine_type, c.transmission, c.wheel_drive,
-	b.brand_name, m.model_name
+	b.brand_name, m.model_name, sl.is_hidden
 	FROM sell_lots sl
 	JOIN cars c ON sl.car_id = c.car_id
 	JOIN brands b ON c.brand_id = b.brand_id
@@ -326,10 +353,10 @@ func (r *PostgresLotsRepo) GetUserPostedLots(userID int) (*[]domain.Lot, error)
 	WHERE seller_id = $1`
 
 	// strUserID, _ := strconv.Atoi(userID)
-	queryRows, err := r.db.Query(query, userID)
+	queryRows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
-		slog.Debug("Лоти від користувача не знайдені в БД", "err", err.Error(), "userID", userID)
-		return nil, err
+		logger.FromContext(ctx).Debug("Лоти від користувача не знайдені в БД", "err", err.Error(), "userID", userID)
+		return nil, dbError(err, nil)
 	}
 	defer queryRows.Close()
 
@@ -344,10 +371,10 @@ func (r *PostgresLotsRepo) GetUserPostedLots(userID int) (*[]domain.Lot, error)
 			&lot.Car.Mileage, &lot.Car.Color, &lot.Description, &images,
 			&lot.Car.CarID, &lot.Car.MadeYear, &lot.Car.Engine,
 			&lot.Car.Transmission, &lot.Car.WheelDrive,
-			&lot.Car.Brand, &lot.Car.Model,
+			&lot.Car.Brand, &lot.Car.Model, &lot.IsHidden,
 		)
 		if err != nil {
-			slog.Debug("Помилка при скануванні", "err", err.Error(), "LotID", lot.LotID)
+			logger.FromContext(ctx).Debug("Помилка при скануванні", "err", err.Error(), "LotID", lot.LotID)
 			continue
 		}
 
@@ -359,11 +386,18 @@ func (r *PostgresLotsRepo) GetUserPostedLots(userID int) (*[]domain.Lot, error)
 
 		lots = append(lots, lot)
 	}
+	if err := queryRows.Err(); err != nil {
+		logger.FromContext(ctx).Debug("Помилка читання лотів", "err", err.Error())
+		return nil, dbError(err, nil)
+	}
 
 	return &lots, nil
 }
 
-func (r *PostgresLotsRepo) GetUserLikedLots(userID int) (*[]domain.Lot, error) {
+func (r *PostgresLotsRepo) GetUserLikedLots(ctx context.Context, userID int) (*[]domain.Lot, error) {
+	ctx, done := r.begin(ctx, "GetUserLikedLots")
+	defer done()
+
 	query := `
 	SELECT sl.lot_id, sl.seller_id, 
   sl.postdate, sl.sale_price, sl.sale_status, sl.vin_code, 
@@ -375,13 +409,13 @@ func (r *PostgresLotsRepo) GetUserLikedLots(userID int) (*[]domain.Lot, error) {
 	JOIN cars c ON sl.car_id = c.car_id
 	JOIN brands b ON c.brand_id = b.brand_id
 	JOIN models m ON c.model_id = m.model_id
-	WHERE ll.user_id = $1;`
+	WHERE ll.user_id = $1 AND NOT sl.is_hidden;`
 
 	// strUserID, _ := strconv.Atoi(userID)
-	queryRows, err := r.db.Query(query, userID)
+	queryRows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
-		slog.Debug("Лоти лайкнуті користувачем не знайдені в БД", "err", err.Error())
-		return nil, err
+		logger.FromContext(ctx).Debug("Лоти лайкнуті користувачем не знайдені в БД", "err", err.Error())
+		return nil, dbError(err, nil)
 	}
 	defer queryRows.Close()
 
@@ -399,7 +433,7 @@ func (r *PostgresLotsRepo) GetUserLikedLots(userID int) (*[]domain.Lot, error) {
 			&lot.Car.Brand, &lot.Car.Model,
 		)
 		if err != nil {
-			slog.Debug("Помилка при скануванні", "err", err.Error())
+			logger.FromContext(ctx).Debug("Помилка при скануванні", "err", err.Error())
 			continue
 		}
 
@@ -411,24 +445,31 @@ func (r *PostgresLotsRepo) GetUserLikedLots(userID int) (*[]domain.Lot, error) {
 
 		lots = append(lots, lot)
 	}
+	if err := queryRows.Err(); err != nil {
+		logger.FromContext(ctx).Debug("Помилка читання лотів", "err", err.Error())
+		return nil, dbError(err, nil)
+	}
 
 	return &lots, nil
 }
 
-func (r *PostgresLotsRepo) CreateLot(ctx context.Context, lot *domain.Lot) error {
+func (r *PostgresLotsRepo) CreateLot(ctx context.Context, lot *domain.Lot, uploads *domain.UploadClaim) error {
+	ctx, done := r.begin(ctx, "CreateLot")
+	defer done()
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return dbError(err, nil)
 	}
 	defer tx.Rollback()
 
-	var saleStatus = "Продається"
+	var saleStatus = domain.SaleStatusForSale
 
 	var brandID int
 	err = tx.QueryRowContext(ctx, "SELECT brand_id FROM brands WHERE brand_name = $1", lot.Car.Brand).Scan(&brandID)
 	if err != nil {
-		slog.Debug("Бренди не знайдені в БД", "err", err.Error())
-		return err
+		logger.FromContext(ctx).Debug("Бренди не знайдені в БД", "err", err.Error())
+		return dbError(err, domain.NewValidation(domain.CodeInvalidLot, map[string]string{"Brand": "unknown"}))
 	}
 
 	var modelID int
@@ -437,8 +478,8 @@ func (r *PostgresLotsRepo) CreateLot(ctx context.Context, lot *domain.Lot) error
 		WHERE model_name = $1 AND brand_id = $2
 	`, lot.Car.Model, brandID).Scan(&modelID)
 	if err != nil {
-		slog.Debug("Модель не знайдено в БД", "err", err.Error())
-		return err
+		logger.FromContext(ctx).Debug("Модель не знайдено в БД", "err", err.Error())
+		return dbError(err, domain.NewValidation(domain.CodeInvalidLot, map[string]string{"Model": "unknown"}))
 	}
 
 	var carID int
@@ -448,8 +489,8 @@ func (r *PostgresLotsRepo) CreateLot(ctx context.Context, lot *domain.Lot) error
 		RETURNING car_id
 	`, lot.Car.MadeYear, lot.Car.Engine, lot.Car.Transmission, lot.Car.WheelDrive, brandID, modelID, lot.Description).Scan(&carID)
 	if err != nil {
-		slog.Debug("Помилка при додаванні машини", "err", err.Error())
-		return err
+		logger.FromContext(ctx).Debug("Помилка при додаванні машини", "err", err.Error())
+		return dbError(err, nil)
 	}
 
 	_, err = tx.ExecContext(ctx, `
@@ -461,73 +502,266 @@ func (r *PostgresLotsRepo) CreateLot(ctx context.Context, lot *domain.Lot) error
 	`,
 		lot.SellerID, carID, lot.SalePrice, saleStatus,
 		lot.Car.VinCode, lot.Car.Mileage, lot.Car.Color,
-		lot.Description, pq.Array(lot.Images),
+		lot.Description, imagesArray(lot.Images),
 	)
 	if err != nil {
-		slog.Debug("Помилка у додаванні лота", "err", err.Error(), "SellerID: ", lot.SellerID)
+		logger.FromContext(ctx).Debug("Помилка у додаванні лота", "err", err.Error(), "SellerID: ", lot.SellerID)
+		return dbError(err, nil)
+	}
+
+	if err := consumeUploadedImages(ctx, tx, uploads); err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	return dbError(tx.Commit(), nil)
 }
 
-func (r *PostgresLotsRepo) UpdateLot(ctx context.Context, lot *domain.Lot) error {
+func (r *PostgresLotsRepo) UpdateLot(ctx context.Context, lot *domain.Lot, uploads *domain.UploadClaim, audit *domain.AdminAction) error {
+	ctx, done := r.begin(ctx, "UpdateLot")
+	defer done()
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return dbError(err, nil)
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		} else {
-			tx.Commit()
-		}
-	}()
+	defer tx.Rollback()
 
 	_, err = tx.ExecContext(ctx, `
 		UPDATE cars SET brand_id = $1, model_id = $2, made_year = $3, engine_type = $4, transmission = $5, wheel_drive = $6
 		WHERE car_id = $7
 	`, lot.Car.BrandID, lot.Car.ModelID, lot.Car.MadeYear, lot.Car.Engine, lot.Car.Transmission, lot.Car.WheelDrive, lot.Car.CarID)
 	if err != nil {
-		return err
+		logger.FromContext(ctx).Debug("Помилка оновлення машини", "err", err.Error(), "CarID", lot.Car.CarID)
+		return dbError(err, nil)
 	}
 
-	_, err = tx.ExecContext(ctx, `
+	result, err := tx.ExecContext(ctx, `
 		UPDATE sell_lots SET seller_id = $1, sale_price = $2, sale_status = $3, vin_code = $4, color = $5, mileage = $6, description = $7, images_paths = $8
 		WHERE lot_id = $9
-	`, lot.SellerID, lot.SalePrice, lot.SaleStatus, lot.Car.VinCode, lot.Car.Color, lot.Car.Mileage, lot.Description, pq.Array(lot.Images), lot.LotID)
+	`, lot.SellerID, lot.SalePrice, lot.SaleStatus, lot.Car.VinCode, lot.Car.Color, lot.Car.Mileage, lot.Description, imagesArray(lot.Images), lot.LotID)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Помилка оновлення лота", "err", err.Error(), "LotID", lot.LotID)
+		return dbError(err, nil)
+	}
+	if err := checkAffected(result, lotNotFound(lot.LotID)); err != nil {
+		return err
+	}
+
+	if err := consumeUploadedImages(ctx, tx, uploads); err != nil {
+		return err
+	}
+
+	if err := recordAdminAction(ctx, tx, audit); err != nil {
+		return err
+	}
 
-	return err
+	return dbError(tx.Commit(), nil)
 }
 
-func (r *PostgresLotsRepo) DeleteLot(ctx context.Context, lotID int) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM sell_lots WHERE lot_id = $1`, lotID)
-	return err
+func (r *PostgresLotsRepo) DeleteLot(ctx context.Context, lotID int, audit *domain.AdminAction) error {
+	ctx, done := r.begin(ctx, "DeleteLot")
+	defer done()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError(err, nil)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM sell_lots WHERE lot_id = $1`, lotID)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Помилка видалення лота", "err", err.Error(), "LotID", lotID)
+		return dbError(err, nil)
+	}
+	if err := checkAffected(result, lotNotFound(lotID)); err != nil {
+		return err
+	}
+
+	if err := recordAdminAction(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	return dbError(tx.Commit(), nil)
 }
 
-func (r *PostgresLotsRepo) LikeLot(userID, lotID int) error {
+func (r *PostgresLotsRepo) LikeLot(ctx context.Context, userID, lotID int) error {
+	ctx, done := r.begin(ctx, "LikeLot")
+	defer done()
+
 	query := `INSERT INTO liked_lots (user_id, lot_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
-	_, err := r.db.Exec(query, userID, lotID)
+	_, err := r.db.ExecContext(ctx, query, userID, lotID)
 	if err != nil {
-		slog.Debug("Не вдалося додати лайк", "err", err.Error())
-		return err
+		logger.FromContext(ctx).Debug("Не вдалося додати лайк", "err", err.Error())
+		if pqCode(err) == foreignKeyViolation {
+			return lotNotFound(lotID).Wrap(err)
+		}
+		return dbError(err, nil)
 	}
 
 	return nil
 }
 
-func (r *PostgresLotsRepo) UnlikeLot(userID, lotID int) error {
+func (r *PostgresLotsRepo) UnlikeLot(ctx context.Context, userID, lotID int) error {
+	ctx, done := r.begin(ctx, "UnlikeLot")
+	defer done()
+
 	query := `DELETE FROM liked_lots WHERE user_id = $1 AND lot_id = $2`
-	_, err := r.db.Exec(query, userID, lotID)
+	_, err := r.db.ExecContext(ctx, query, userID, lotID)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Не вдалося прибрати лайк", "err", err.Error())
+		return dbError(err, nil)
+	}
+
+	return nil
+}
+
+// MarkLotAsSold переводить лот у продані. Умова на статус входить в сам UPDATE,
+// тож із двох одночасних продажів успішним буде лише один, а другий отримає
+// lot_already_sold. visibleOnly — продаж покупцем, якому прихований лот недоступний.
+func (r *PostgresLotsRepo) MarkLotAsSold(ctx context.Context, lotID int, visibleOnly bool) error {
+	ctx, done := r.begin(ctx, "MarkLotAsSold")
+	defer done()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE sell_lots SET sale_status = $1
+		WHERE lot_id = $2 AND sale_status <> $1 AND (NOT $3 OR NOT is_hidden)
+	`, domain.SaleStatusSold, lotID, visibleOnly)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Помилка позначення лота проданим", "err", err.Error(), "LotID", lotID)
+		return dbError(err, nil)
+	}
+
+	affected, err := result.RowsAffected()
 	if err != nil {
-		slog.Debug("Не вдалося прибрати лайк", "err", err.Error())
 		return err
 	}
+	if affected > 0 {
+		return nil
+	}
+
+	// Жоден рядок не змінено: з'ясовуємо причину, щоб відповісти 404 або 409
+	var hidden bool
+	err = r.db.QueryRowContext(ctx, `SELECT is_hidden FROM sell_lots WHERE lot_id = $1`, lotID).Scan(&hidden)
+	if err != nil {
+		return dbError(err, lotNotFound(lotID))
+	}
+	if visibleOnly && hidden {
+		return lotNotFound(lotID)
+	}
+
+	return domain.NewConflict(domain.CodeLotAlreadySold, fmt.Sprintf("лот %d", lotID))
+}
+
+func (r *PostgresLotsRepo) GetLotsImages(ctx context.Context) (map[int][]string, error) {
+	ctx, done := r.begin(ctx, "GetLotsImages")
+	defer done()
+
+	queryRows, err := r.db.QueryContext(ctx, `
+		SELECT lot_id, images_paths FROM sell_lots
+		WHERE cardinality(images_paths) > 0
+	`)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Помилка отримання зображень лотів", "err", err.Error())
+		return nil, dbError(err, nil)
+	}
+	defer queryRows.Close()
+
+	lotsImages := make(map[int][]string)
+
+	for queryRows.Next() {
+		var lotID int
+		var images pq.StringArray
+		if err := queryRows.Scan(&lotID, &images); err != nil {
+			logger.FromContext(ctx).Debug("Помилка при скануванні", "err", err.Error())
+			return nil, dbError(err, nil)
+		}
+
+		lotsImages[lotID] = images
+	}
+
+	return lotsImages, dbError(queryRows.Err(), nil)
+}
+
+func (r *PostgresLotsRepo) SetLotHidden(ctx context.Context, lotID int, hidden bool, audit *domain.AdminAction) error {
+	ctx, done := r.begin(ctx, "SetLotHidden")
+	defer done()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError(err, nil)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE sell_lots SET is_hidden = $1 WHERE lot_id = $2`, hidden, lotID)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Помилка зміни видимості лота", "err", err.Error(), "LotID", lotID)
+		return dbError(err, nil)
+	}
+	if err := checkAffected(result, lotNotFound(lotID)); err != nil {
+		return err
+	}
+
+	if err := recordAdminAction(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	return dbError(tx.Commit(), nil)
+}
+
+// recordAdminAction пише дію адміністратора в журнал у транзакції зміни,
+// тож невдалий запис журналу відкочує і саму зміну. nil — дія власника лота.
+func recordAdminAction(ctx context.Context, tx *sql.Tx, action *domain.AdminAction) error {
+	if action == nil {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO admin_actions (admin_id, lot_id, action, reason, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`, action.AdminID, action.LotID, action.Action, action.Reason)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Помилка запису дії адміністратора", "err", err.Error(), "action", action.Action)
+		return dbError(err, nil)
+	}
 
 	return nil
 }
 
-func (r *PostgresLotsRepo) MarkLotAsSold(lotID int) error {
-	_, err := r.db.Exec(`UPDATE sell_lots SET sale_status = 'Продано' WHERE lot_id = $1`, lotID)
-	return err
+// consumeUploadedImages позначає зображення з сесії прямого завантаження
+// використаними в транзакції запису лота. Повторне використання будь-якого
+// з них відхиляє всю зміну.
+func consumeUploadedImages(ctx context.Context, tx *sql.Tx, uploads *domain.UploadClaim) error {
+	if uploads == nil || len(uploads.ImageIDs) == 0 {
+		return nil
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO consumed_upload_images (image_id, user_id)
+		SELECT unnest($1::text[]), $2
+		ON CONFLICT (image_id) DO NOTHING
+	`, pq.Array(uploads.ImageIDs), uploads.UserID)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Помилка позначення зображень використаними", "err", err.Error())
+		return dbError(err, nil)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(affected) != len(uploads.ImageIDs) {
+		return domain.NewConflict(domain.CodeImageAlreadyUsed, fmt.Sprintf("користувач %d, зображення %v", uploads.UserID, uploads.ImageIDs))
+	}
+
+	return nil
+}
+
+// imagesArray передає лот без зображень як порожній масив, а не NULL:
+// images_paths оголошено NOT NULL
+func imagesArray(images []string) any {
+	if images == nil {
+		images = []string{}
+	}
+
+	return pq.Array(images)
 }
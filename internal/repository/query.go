@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"lots-service/internal/lib/metrics"
+	"lots-service/internal/lib/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// beginQuery обмежує метод репозиторію таймаутом з конфігу і повертає done,
+// який звільняє контекст і записує тривалість методу в метрики і трасу.
+// Скасування ctx (наприклад, клієнт закрив з'єднання) перериває запит
+// незалежно від таймауту.
+func beginQuery(ctx context.Context, timeout time.Duration, method string) (context.Context, func()) {
+	start := time.Now()
+
+	ctx, span := tracing.Start(ctx, method,
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation.name", method),
+	)
+
+	var cancel context.CancelFunc
+	if timeout <= 0 {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	return ctx, func() {
+		if err := ctx.Err(); errors.Is(err, context.DeadlineExceeded) {
+			tracing.Fail(span, err)
+		}
+
+		cancel()
+		span.End()
+		metrics.ObserveQuery(method, time.Since(start))
+	}
+}
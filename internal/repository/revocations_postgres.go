@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"lots-service/internal/lib/logger"
+	"lots-service/pkg/auth"
+	"time"
+)
+
+// PostgresRevocations — спільний для всіх інстансів denylist токенів
+type PostgresRevocations struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+}
+
+// queryTimeout обмежує тривалість кожного запиту, 0 — без обмеження
+func NewPostgresRevocations(db *sql.DB, queryTimeout time.Duration) *PostgresRevocations {
+	return &PostgresRevocations{db: db, queryTimeout: queryTimeout}
+}
+
+func (r *PostgresRevocations) begin(ctx context.Context, method string) (context.Context, func()) {
+	return beginQuery(ctx, r.queryTimeout, "PostgresRevocations."+method)
+}
+
+func (r *PostgresRevocations) IsRevoked(ctx context.Context, principal *auth.Principal) (bool, error) {
+	ctx, done := r.begin(ctx, "IsRevoked")
+	defer done()
+
+	var revoked bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			EXISTS (SELECT 1 FROM revoked_tokens WHERE token_id = $1 AND expires_at > NOW())
+			OR EXISTS (SELECT 1 FROM revoked_users WHERE user_id = $2 AND ($3::timestamptz IS NULL OR $3 < date_trunc('second', revoked_at)))
+	`, principal.TokenID, principal.UserID, nullTime(principal.IssuedAt)).Scan(&revoked)
+	if err != nil {
+		logger.FromContext(ctx).Debug("Помилка перевірки відкликання токена", "err", err.Error())
+		return false, dbError(err, nil)
+	}
+
+	return revoked, nil
+}
+
+func (r *PostgresRevocations) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ctx, done := r.begin(ctx, "RevokeToken")
+	defer done()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO revoked_tokens (token_id, expires_at) VALUES ($1, $2)
+		ON CONFLICT (token_id) DO UPDATE SET expires_at = EXCLUDED.expires_at
+	`, tokenID, expiresAt)
+	return dbError(err, nil)
+}
+
+func (r *PostgresRevocations) RevokeUser(ctx context.Context, userID int, revokedAt time.Time) error {
+	ctx, done := r.begin(ctx, "RevokeUser")
+	defer done()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO revoked_users (user_id, revoked_at) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET revoked_at = EXCLUDED.revoked_at
+	`, userID, revokedAt)
+	return dbError(err, nil)
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"lots-service/internal/lib/responseHTTP"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HealthCheck — залежність, без якої сервіс не готовий приймати трафік
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Health обслуговує /healthz і /readyz. Готовність вимикається на початку
+// завершення роботи, щоб балансувальник встиг прибрати інстанс з ротації.
+type Health struct {
+	ready   atomic.Bool
+	timeout time.Duration
+	checks  []HealthCheck
+}
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+func NewHealth(timeout time.Duration, checks ...HealthCheck) *Health {
+	h := &Health{timeout: timeout, checks: checks}
+	h.ready.Store(true)
+
+	return h
+}
+
+func (h *Health) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Liveness відповідає 200, доки процес здатен обробляти запити
+func (h *Health) Liveness(w http.ResponseWriter, r *http.Request) {
+	responseHTTP.JSONResp(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// Readiness перевіряє залежності паралельно з таймаутом
+func (h *Health) Readiness(w http.ResponseWriter, r *http.Request) {
+	if !h.ready.Load() {
+		responseHTTP.JSONResp(w, http.StatusServiceUnavailable, healthResponse{Status: "shutting_down"})
+		return
+	}
+
+	ctx := r.Context()
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	results := make(map[string]string, len(h.checks))
+	status, code := "ok", http.StatusOK
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result := "ok"
+			if err := check.Check(ctx); err != nil {
+				slog.Warn("Перевірка готовності не пройдена", "check", check.Name, "err", err.Error())
+				result = "error"
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[check.Name] = result
+			if result != "ok" {
+				status, code = "not_ready", http.StatusServiceUnavailable
+			}
+		}()
+	}
+	wg.Wait()
+
+	responseHTTP.JSONResp(w, code, healthResponse{Status: status, Checks: results})
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"encoding/json"
+	"lots-service/internal/domain"
+	"lots-service/internal/lib/i18n"
+	"lots-service/internal/lib/logger"
+	"lots-service/internal/lib/responseHTTP"
+	"net/http"
+	"strings"
+)
+
+type logLevelBody struct {
+	Level string `json:"level"`
+}
+
+// getLogLevel повертає поточний рівень логування
+func getLogLevel(w http.ResponseWriter, r *http.Request) {
+	responseHTTP.JSONResp(w, http.StatusOK, logLevelBody{Level: strings.ToLower(logger.Level().String())})
+}
+
+// setLogLevel змінює рівень логування без перезапуску сервісу.
+// Зміна діє до перезапуску, після нього рівень знову береться з конфігу.
+func setLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req logLevelBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		responseHTTP.JSONError(w, r, http.StatusBadRequest, domain.CodeInvalidRequest)
+		return
+	}
+
+	level, err := logger.ParseLevel(req.Level)
+	if err != nil {
+		responseHTTP.JSONError(w, r, http.StatusBadRequest, i18n.MsgInvalidLogLevel)
+		return
+	}
+
+	previous := logger.Level()
+	logger.SetLevel(level)
+	logger.FromContext(r.Context()).Warn("Змінено рівень логування", "from", previous.String(), "to", level.String())
+
+	responseHTTP.JSONResp(w, http.StatusOK, logLevelBody{Level: strings.ToLower(level.String())})
+}
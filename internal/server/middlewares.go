@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"lots-service/internal/lib/logger"
+	"lots-service/internal/lib/metrics"
+	"lots-service/internal/lib/responseHTTP"
+	"lots-service/pkg/auth"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// unmatchedRoute — мітка для запитів, яким не знайшовся маршрут, щоб сирі
+// шляхи не потрапляли в метрики
+const unmatchedRoute = "unmatched"
+
+const (
+	requestIDHeader = "X-Request-ID"
+	// Довші або з недрукованими символами ID від клієнта замінюються новими
+	maxRequestIDLength = 128
+)
+
+// statusRecorder запам'ятовує статус і розмір відповіді для метрик і логів
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+	wrote  bool
+}
+
+func newStatusRecorder(w http.ResponseWriter) *statusRecorder {
+	return &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.wrote = true
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wrote = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+
+	return n, err
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// metricsMiddleware рахує запити і їх тривалість за шаблоном маршруту mux
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := newStatusRecorder(w)
+
+		next.ServeHTTP(recorder, r)
+
+		metrics.ObserveHTTP(routeTemplate(r), r.Method, recorder.status, time.Since(start))
+	})
+}
+
+// requestCaller — хто виконав запит; заповнюється після авторизації,
+// щоб потрапити в рядок access-логу
+type requestCaller struct {
+	userID  int
+	service string
+}
+
+type requestCallerKey struct{}
+
+// requestLogMiddleware присвоює запиту X-Request-ID (або бере його з заголовка),
+// кладе в контекст логер з request_id і маршрутом і пише один рядок access-логу
+func requestLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := requestIDFrom(r)
+		w.Header().Set(requestIDHeader, requestID)
+
+		caller := &requestCaller{}
+		ctx := context.WithValue(r.Context(), requestCallerKey{}, caller)
+		ctx = logger.With(ctx, "request_id", requestID, "method", r.Method, "route", routeTemplate(r))
+
+		recorder := newStatusRecorder(w)
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		args := []any{
+			"status", recorder.status,
+			"duration", time.Since(start).String(),
+			"bytes", recorder.bytes,
+			"path", r.URL.Path,
+			"remote", r.RemoteAddr,
+		}
+		if caller.userID != 0 {
+			args = append(args, "user_id", caller.userID)
+		}
+		if caller.service != "" {
+			args = append(args, "service", caller.service)
+		}
+
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logger.FromContext(ctx).Log(ctx, level, "HTTP-запит", args...)
+	})
+}
+
+// requestCallerMiddleware ставиться після авторизації і додає користувача
+// або внутрішній сервіс до логера запиту
+func requestCallerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		caller, _ := ctx.Value(requestCallerKey{}).(*requestCaller)
+
+		if userID, ok := auth.UserIDFromContext(ctx); ok {
+			ctx = logger.With(ctx, "user_id", userID)
+			if caller != nil {
+				caller.userID = userID
+			}
+		} else if service, ok := auth.ServiceCallerFromContext(ctx); ok {
+			ctx = logger.With(ctx, "service", service.Name)
+			if caller != nil {
+				caller.service = service.Name
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// WriteAuthError — ErrorWriter для pkg/auth: відмови авторизації рендеряться
+// тим самим локалізованим JSON-форматом, що й інші помилки сервісу
+func WriteAuthError(w http.ResponseWriter, r *http.Request, err *auth.Error) {
+	logger.FromContext(r.Context()).Debug("Помилка авторизації", "code", err.Code, "err", err.Error())
+	responseHTTP.WriteError(w, r, err)
+}
+
+// recoverMiddleware перехоплює паніку в обробнику, логує стек і відповідає
+// JSON 500 замість обірваного з'єднання. Якщо обробник уже почав відповідь,
+// 500 дописати не можна, тож з'єднання обривається через http.ErrAbortHandler,
+// щоб клієнт не прийняв обрізане тіло за успішну відповідь.
+func recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := newStatusRecorder(w)
+
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			// Так net/http сигналізує навмисне переривання відповіді
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+
+			logger.FromContext(r.Context()).Error("Паніка в обробнику запиту", "panic", fmt.Sprint(p), "stack", string(debug.Stack()), "response_started", recorder.wrote)
+			if recorder.wrote {
+				panic(http.ErrAbortHandler)
+			}
+			responseHTTP.WriteError(w, r, fmt.Errorf("panic: %v", p))
+		}()
+
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// BodyLimits обмежує розмір тіла запиту. Для multipart-форм із зображеннями
+// діє окремий, більший ліміт.
+type BodyLimits struct {
+	MaxBodyBytes   int64
+	MaxUploadBytes int64
+}
+
+func bodyLimitMiddleware(limits BodyLimits) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := limits.MaxBodyBytes
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+				limit = limits.MaxUploadBytes
+			}
+
+			if limit > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func requestIDFrom(r *http.Request) string {
+	id := r.Header.Get(requestIDHeader)
+	if id == "" || len(id) > maxRequestIDLength {
+		return uuid.NewString()
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return uuid.NewString()
+		}
+	}
+
+	return id
+}
+
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return unmatchedRoute
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return unmatchedRoute
+	}
+
+	return template
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// headerCounter рахує виклики WriteHeader, яких httptest.ResponseRecorder не показує
+type headerCounter struct {
+	*httptest.ResponseRecorder
+	writeHeaders int
+}
+
+func (c *headerCounter) WriteHeader(status int) {
+	c.writeHeaders++
+	c.ResponseRecorder.WriteHeader(status)
+}
+
+func TestRecoverMiddlewarePanicBeforeWrite(t *testing.T) {
+	rec := &headerCounter{ResponseRecorder: httptest.NewRecorder()}
+	handler := recoverMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	var resp struct {
+		ErrorCode string `json:"error_code"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if resp.ErrorCode == "" {
+		t.Fatalf("error_code is empty")
+	}
+}
+
+func TestRecoverMiddlewareAbortsStartedResponse(t *testing.T) {
+	rec := &headerCounter{ResponseRecorder: httptest.NewRecorder()}
+	handler := recoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("partial"))
+		panic("boom")
+	}))
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Fatalf("recovered %v, want http.ErrAbortHandler", p)
+		}
+		if rec.writeHeaders != 1 || rec.Body.String() != "partial" {
+			t.Fatalf("WriteHeader calls = %d, body = %q, want the handler's output only", rec.writeHeaders, rec.Body.String())
+		}
+	}()
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	t.Fatalf("started response was not aborted")
+}
+
+// Через справжній сервер: клієнт отримує обрив з'єднання, а не обрізане тіло
+func TestRecoverMiddlewareAbortedResponseFailsClient(t *testing.T) {
+	srv := httptest.NewUnstartedServer(recoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("partial"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("flush: %v", err)
+			return
+		}
+		panic("boom")
+	})))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.ReadAll(resp.Body); err == nil {
+		t.Fatalf("client read the truncated response without error")
+	}
+}
+
+func TestRecoverMiddlewareRepanicsAbortHandler(t *testing.T) {
+	handler := recoverMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Fatalf("recovered %v, want http.ErrAbortHandler", p)
+		}
+	}()
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	t.Fatalf("ErrAbortHandler was swallowed")
+}
@@ -1,18 +1,34 @@
 package server
 
 import (
-	"log/slog"
 	"lots-service/internal/delivery/http_handlers"
+	"lots-service/internal/lib/i18n"
+	"lots-service/internal/lib/metrics"
 	"lots-service/internal/lib/responseHTTP"
+	"lots-service/internal/lib/tracing"
 	"lots-service/pkg/auth"
 	"net/http"
 
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 )
 
-func NewRouter(lotsHandler *http_handlers.LotsHandler) http.Handler {
+func NewRouter(lotsHandler *http_handlers.LotsHandler, authenticator *auth.Authenticator, apiKeys *auth.APIKeyAuthenticator, revoker auth.Revoker, health *Health, limits BodyLimits) http.Handler {
 	router := mux.NewRouter()
+	router.Use(otelmux.Middleware(tracing.ServiceName), metricsMiddleware, requestLogMiddleware, recoverMiddleware, bodyLimitMiddleware(limits))
+
+	router.HandleFunc("/healthz", health.Liveness).Methods("GET")
+	router.HandleFunc("/readyz", health.Readiness).Methods("GET")
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+
+	withAuth := func(h http.HandlerFunc) http.Handler { return authenticator.AuthMiddleware(requestCallerMiddleware(h)) }
+	withOptionalAuth := func(h http.HandlerFunc) http.Handler {
+		return authenticator.OptionalAuthMiddleware(requestCallerMiddleware(h))
+	}
+	withScope := func(scope string, h http.Handler) http.Handler {
+		return apiKeys.RequireScope(scope)(requestCallerMiddleware(h))
+	}
 
 	router.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:3011/swagger/doc.json"),
@@ -21,37 +37,57 @@ func NewRouter(lotsHandler *http_handlers.LotsHandler) http.Handler {
 		httpSwagger.DomID("swagger-ui"),
 	))
 
-	router.Handle("/api/lots/id/{lot_id}", auth.OptionalAuthMiddleware(lotsHandler.GetLotByID)).Methods("GET")
-	router.Handle("/api/lots/filtered", auth.OptionalAuthMiddleware(lotsHandler.GetLotsPageByParams)).Methods("GET")
+	router.Handle("/api/lots/id/{lot_id}", withOptionalAuth(lotsHandler.GetLotByID)).Methods("GET")
+	router.Handle("/api/lots/filtered", withOptionalAuth(lotsHandler.GetLotsPageByParams)).Methods("GET")
 
-	router.Handle("/api/lots/sell_lots", auth.OptionalAuthMiddleware(lotsHandler.GetLotsPage)).Methods("GET")
+	router.Handle("/api/lots/sell_lots", withOptionalAuth(lotsHandler.GetLotsPage)).Methods("GET")
 	router.HandleFunc("/api/lots/sell_lots_count", lotsHandler.GetLotsCount).Methods("GET")
 	router.HandleFunc("/api/lots/sell_lots_filtered_count", lotsHandler.GetLotsByParamsCount).Methods("GET")
 
 	router.HandleFunc("/api/lots/brands", lotsHandler.GetBrands).Methods("GET")
 	router.HandleFunc("/api/lots/models", lotsHandler.GetModels).Methods("GET")
 
-	router.Handle("/api/lots/user_posted_lots", auth.AuthMiddleware(lotsHandler.GetUserPostedLots)).Methods("GET")
-	router.Handle("/api/lots/user_liked_lots", auth.AuthMiddleware(lotsHandler.GetUserLikedLots)).Methods("GET")
+	router.Handle("/api/lots/user_posted_lots", withAuth(lotsHandler.GetUserPostedLots)).Methods("GET")
+	router.Handle("/api/lots/user_liked_lots", withAuth(lotsHandler.GetUserLikedLots)).Methods("GET")
+
+	router.Handle("/api/lots/upload_sessions", withAuth(lotsHandler.CreateUploadSession)).Methods("POST")
+	router.Handle("/api/lots/create_lot", withAuth(lotsHandler.CreateLot)).Methods("POST")
+	router.Handle("/api/lots/update_lot/{lot_id}", withAuth(lotsHandler.UpdateLot)).Methods("PUT")
+	router.Handle("/api/lots/delete_lot/{lot_id}", withAuth(lotsHandler.DeleteLot)).Methods("DELETE")
+
+	router.Handle("/api/lots/likes/{lot_id}", withAuth(lotsHandler.LikeLot)).Methods("POST")
+	router.Handle("/api/lots/likes/{lot_id}", withAuth(lotsHandler.UnlikeLot)).Methods("DELETE")
+
+	router.Handle("/api/lots/buy_lot/{lot_id}", withAuth(lotsHandler.BuyLotHandler)).Methods("PUT")
+
+	admin := router.PathPrefix("/api/admin").Subrouter()
+	admin.Use(authenticator.AuthMiddleware, requestCallerMiddleware, authenticator.RequireRole(auth.RoleAdmin, auth.RoleModerator))
+
+	admin.HandleFunc("/lots/{lot_id}", lotsHandler.AdminDeleteLot).Methods("DELETE")
+	admin.HandleFunc("/lots/{lot_id}/visibility", lotsHandler.AdminSetLotVisibility).Methods("PUT")
+	admin.Handle("/lots/{lot_id}", authenticator.RequireRole(auth.RoleAdmin)(http.HandlerFunc(lotsHandler.AdminUpdateLot))).Methods("PUT")
+
+	admin.Handle("/log_level", authenticator.RequireRole(auth.RoleAdmin)(http.HandlerFunc(getLogLevel))).Methods("GET")
+	admin.Handle("/log_level", authenticator.RequireRole(auth.RoleAdmin)(http.HandlerFunc(setLogLevel))).Methods("PUT")
 
-	router.Handle("/api/lots/create_lot", auth.AuthMiddleware(lotsHandler.CreateLot)).Methods("POST")
-	router.Handle("/api/lots/update_lot/{lot_id}", auth.AuthMiddleware(lotsHandler.UpdateLot)).Methods("PUT")
-	router.Handle("/api/lots/delete_lot/{lot_id}", auth.AuthMiddleware(lotsHandler.DeleteLot)).Methods("DELETE")
+	// Маршрути для інших сервісів CarVia, авторизація за API-ключем
+	internal := router.PathPrefix("/internal/lots").Subrouter()
 
-	router.Handle("/api/lots/likes/{lot_id}", auth.AuthMiddleware(lotsHandler.LikeLot)).Methods("POST")
-	router.Handle("/api/lots/likes/{lot_id}", auth.AuthMiddleware(lotsHandler.UnlikeLot)).Methods("DELETE")
+	internal.Handle("/{lot_id}", withScope(auth.ScopeLotsRead, http.HandlerFunc(lotsHandler.InternalGetLot))).Methods("GET")
+	internal.Handle("/{lot_id}/sold", withScope(auth.ScopeLotsMarkSold, http.HandlerFunc(lotsHandler.InternalMarkLotSold))).Methods("PUT")
 
-	router.Handle("/api/lots/buy_lot/{lot_id}", auth.AuthMiddleware(lotsHandler.BuyLotHandler)).Methods("PUT")
+	if revoker != nil {
+		router.Handle("/internal/auth/revocations", withScope(auth.ScopeAuthRevoke, http_handlers.RevocationHandler(revoker))).Methods("POST")
+	}
 
-	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		slog.Debug("Маршрут не знайдено", "method", r.Method, "path", r.URL.Path)
-		responseHTTP.JSONError(w, http.StatusNotFound, "Маршрут не знайдено")
-	})
+	// mux не застосовує Use до цих обробників, тож метрики і логи додаються явно
+	router.NotFoundHandler = metricsMiddleware(requestLogMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		responseHTTP.JSONError(w, r, http.StatusNotFound, i18n.MsgRouteNotFound)
+	})))
 
-	router.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		slog.Debug("Заборонений метод", "method", r.Method, "path", r.URL.Path)
-		responseHTTP.JSONError(w, http.StatusMethodNotAllowed, "Заборонений метод")
-	})
+	router.MethodNotAllowedHandler = metricsMiddleware(requestLogMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		responseHTTP.JSONError(w, r, http.StatusMethodNotAllowed, i18n.MsgMethodNotAllowed)
+	})))
 
 	return router
 }
@@ -11,17 +11,39 @@ import (
 	"time"
 )
 
-func StartServer(router http.Handler, port string, timeout time.Duration) {
+// ServerOptions — параметри http.Server. Нульові таймаути вимикають обмеження,
+// тож у конфігу вони мають значення за замовчуванням.
+type ServerOptions struct {
+	Port              string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	// Скільки чекати завершення активних запитів під час зупинки
+	ShutdownTimeout time.Duration
+	// Скільки чекати після виключення готовності перед зупинкою сервера
+	DrainDelay time.Duration
+}
+
+// StartServer обслуговує запити до SIGINT/SIGTERM. Після сигналу /readyz
+// одразу повертає 503, а сервер чекає DrainDelay перед Shutdown.
+func StartServer(router http.Handler, opts ServerOptions, health *Health) {
 	server := &http.Server{
-		Addr:    fmt.Sprintf(":%s", port),
-		Handler: router,
+		Addr:              fmt.Sprintf(":%s", opts.Port),
+		Handler:           router,
+		ReadHeaderTimeout: opts.ReadHeaderTimeout,
+		ReadTimeout:       opts.ReadTimeout,
+		WriteTimeout:      opts.WriteTimeout,
+		IdleTimeout:       opts.IdleTimeout,
+		MaxHeaderBytes:    opts.MaxHeaderBytes,
 	}
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
-		slog.Info("Lots service running on port: " + port)
+		slog.Info("Lots service running on port: " + opts.Port)
 		err := server.ListenAndServe()
 		if err != nil && err != http.ErrServerClosed {
 			slog.Error("Server error: ", "Error", err)
@@ -29,9 +51,14 @@ func StartServer(router http.Handler, port string, timeout time.Duration) {
 	}()
 
 	<-stop
+	health.SetReady(false)
+	if opts.DrainDelay > 0 {
+		slog.Info("Draining traffic before shutdown...", "delay", opts.DrainDelay)
+		time.Sleep(opts.DrainDelay)
+	}
 	slog.Info("Shutting down server...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), opts.ShutdownTimeout)
 	defer cancel()
 	slog.Info("Shutdown ", "stopcode", server.Shutdown(ctx))
 }
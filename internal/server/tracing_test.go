@@ -0,0 +1,183 @@
+package server
+
+import (
+	"bytes"
+	"database/sql"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"lots-service/internal/delivery/http_handlers"
+	"lots-service/internal/repository"
+	"lots-service/internal/service"
+	"lots-service/pkg/auth"
+
+	"github.com/golang-jwt/jwt/v4"
+	_ "github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Глобальний провайдер можна підмінити лише один раз: трейсери, отримані
+// до цього, прив'язуються до першого встановленого провайдера
+var spans = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	os.Exit(m.Run())
+}
+
+var testSecret = []byte("test-secret")
+
+// newTracedRouter збирає справжній роутер, сервіс і репозиторій. БД
+// недоступна, тож запит до неї завершується помилкою, але спан репозиторію
+// все одно відкривається.
+func newTracedRouter(t *testing.T, storageURL string) http.Handler {
+	t.Helper()
+
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 user=lots dbname=lots sslmode=disable connect_timeout=1")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	authenticator, err := auth.NewAuthenticator(auth.Options{Algorithm: "HS256", Secret: testSecret, ErrorWriter: WriteAuthError})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	apiKeys, err := auth.NewAPIKeyAuthenticator(nil, WriteAuthError)
+	if err != nil {
+		t.Fatalf("NewAPIKeyAuthenticator: %v", err)
+	}
+
+	lotsService := service.NewLotsService(repository.NewPostgresLotsRepo(db, time.Second), storageURL, nil, nil)
+
+	return NewRouter(http_handlers.NewLotsHandler(lotsService), authenticator, apiKeys, nil, NewHealth(0), BodyLimits{})
+}
+
+func newCreateLotRequest(t *testing.T) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range map[string]string{"Brand": "BMW", "Model": "X5", "MadeYear": "2020", "SalePrice": "10000"} {
+		if err := writer.WriteField(name, value); err != nil {
+			t.Fatalf("write field: %v", err)
+		}
+	}
+	part, err := writer.CreateFormFile("NewImages", "photo.jpg")
+	if err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	part.Write([]byte("jpeg"))
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close form: %v", err)
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": 7,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString(testSecret)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/lots/create_lot", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return req
+}
+
+func spansOfTrace(traceID trace.TraceID) map[string]sdktrace.ReadOnlySpan {
+	byName := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range spans.Ended() {
+		if span.SpanContext().TraceID() == traceID {
+			byName[span.Name()] = span
+		}
+	}
+
+	return byName
+}
+
+func TestCreateLotSpanHierarchy(t *testing.T) {
+	var mu sync.Mutex
+	var storageTraceparent string
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if r.URL.Path == "/api/storage/upload_images" {
+			storageTraceparent = r.Header.Get("traceparent")
+		}
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer storage.Close()
+
+	router := newTracedRouter(t, storage.URL)
+
+	// Запит приходить з трасою від клієнта (наприклад, API gateway)
+	const clientTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := newCreateLotRequest(t)
+	req.Header.Set("traceparent", clientTraceparent)
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	clientSpanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	byName := spansOfTrace(traceID)
+
+	route := byName["/api/lots/create_lot"]
+	if route == nil {
+		t.Fatalf("no server span in trace, got %v", spanNames(byName))
+	}
+	if route.Parent().SpanID() != clientSpanID || !route.Parent().IsRemote() {
+		t.Fatalf("server span parent = %v, want remote %v", route.Parent().SpanID(), clientSpanID)
+	}
+
+	// дочірній спан -> батьківський
+	hierarchy := [][2]string{
+		{"LotsService.CreateLot", "/api/lots/create_lot"},
+		{"LotsService.SaveImages", "LotsService.CreateLot"},
+		{"LotsService.StorageRequest", "LotsService.SaveImages"},
+		{"storage upload_images", "LotsService.StorageRequest"},
+		{"PostgresLotsRepo.CreateLot", "LotsService.CreateLot"},
+	}
+	for _, link := range hierarchy {
+		child, parent := byName[link[0]], byName[link[1]]
+		if child == nil || parent == nil {
+			t.Fatalf("missing span %q or %q, got %v", link[0], link[1], spanNames(byName))
+		}
+		if child.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Fatalf("%q parent = %v, want %q (%v)", link[0], child.Parent().SpanID(), link[1], parent.SpanContext().SpanID())
+		}
+	}
+
+	// storage отримує traceparent клієнтського спану
+	storageSpan := byName["storage upload_images"].SpanContext()
+	mu.Lock()
+	defer mu.Unlock()
+	got := propagation.TraceContext{}.Extract(t.Context(), propagation.HeaderCarrier{"Traceparent": {storageTraceparent}})
+	remote := trace.SpanContextFromContext(got)
+	if remote.TraceID() != traceID || remote.SpanID() != storageSpan.SpanID() {
+		t.Fatalf("storage traceparent = %q, want trace %v span %v", storageTraceparent, traceID, storageSpan.SpanID())
+	}
+}
+
+func spanNames(byName map[string]sdktrace.ReadOnlySpan) []string {
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+
+	return names
+}
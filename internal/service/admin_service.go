@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"lots-service/internal/domain"
+	"lots-service/internal/lib/logger"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Дії адміністраторів і модераторів виконуються без перевірки власника лота,
+// тому кожна з них записується в журнал admin_actions у тій самій транзакції,
+// що й зміна лота: без запису в журнал зміна не відбувається
+
+func (s *LotsService) AdminDeleteLot(ctx context.Context, adminID, lotID int, reason string) (err error) {
+	ctx, span := startSpan(ctx, "AdminDeleteLot", attribute.Int("lot.id", lotID))
+	defer func() { endSpan(span, err) }()
+
+	lot, err := s.repo.GetLotByID(ctx, adminID, lotID)
+	if err != nil {
+		return err
+	}
+
+	action := &domain.AdminAction{
+		AdminID: adminID,
+		LotID:   lotID,
+		Action:  domain.AdminActionDeleteLot,
+		Reason:  reason,
+	}
+	if err := s.deleteLot(ctx, lot, action); err != nil {
+		return err
+	}
+
+	logAdminAction(ctx, action)
+	return nil
+}
+
+func (s *LotsService) AdminSetLotHidden(ctx context.Context, adminID, lotID int, hidden bool, reason string) (err error) {
+	ctx, span := startSpan(ctx, "AdminSetLotHidden", attribute.Int("lot.id", lotID))
+	defer func() { endSpan(span, err) }()
+
+	action := &domain.AdminAction{
+		AdminID: adminID,
+		LotID:   lotID,
+		Action:  domain.AdminActionUnhideLot,
+		Reason:  reason,
+	}
+	if hidden {
+		action.Action = domain.AdminActionHideLot
+	}
+
+	if err := s.repo.SetLotHidden(ctx, lotID, hidden, action); err != nil {
+		return err
+	}
+
+	logAdminAction(ctx, action)
+	return nil
+}
+
+func (s *LotsService) AdminUpdateLot(ctx context.Context, adminID int, lot *domain.Lot, newFiles domain.ImageStream, uploaded domain.UploadedImages, deleteImages []string, oldImages []string, reason string) (err error) {
+	ctx, span := startSpan(ctx, "AdminUpdateLot", attribute.Int("lot.id", lot.LotID))
+	defer func() { endSpan(span, err) }()
+
+	existingLot, err := s.repo.GetLotByID(ctx, adminID, lot.LotID)
+	if err != nil {
+		return err
+	}
+
+	lot.SellerID = existingLot.SellerID
+
+	action := &domain.AdminAction{
+		AdminID: adminID,
+		LotID:   lot.LotID,
+		Action:  domain.AdminActionUpdateLot,
+		Reason:  reason,
+	}
+	if err := s.applyLotUpdate(ctx, adminID, existingLot, lot, newFiles, uploaded, deleteImages, oldImages, action); err != nil {
+		return err
+	}
+
+	logAdminAction(ctx, action)
+	return nil
+}
+
+func logAdminAction(ctx context.Context, action *domain.AdminAction) {
+	logger.FromContext(ctx).Info("Дія адміністратора", "adminID", action.AdminID, "lotID", action.LotID, "action", action.Action, "reason", action.Reason)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"lots-service/internal/domain"
+	"lots-service/internal/lib/logger"
+	"time"
+)
+
+// ReconcileImages порівнює вміст сховища з images_paths лотів.
+// Зображення, на які не посилається жоден лот і які старші за gracePeriod,
+// видаляються (крім режиму dryRun), а посилання лотів на відсутні файли
+// потрапляють у звіт як висячі.
+func (s *LotsService) ReconcileImages(ctx context.Context, gracePeriod time.Duration, dryRun bool) (*domain.ImagesReconcileReport, error) {
+	storedImages, err := s.ListImages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("помилка отримання списку зображень: %w", err)
+	}
+
+	lotsImages, err := s.repo.GetLotsImages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("помилка отримання зображень лотів: %w", err)
+	}
+
+	report := &domain.ImagesReconcileReport{
+		Dangling: make(map[int][]string),
+		DryRun:   dryRun,
+	}
+
+	referencedSet := make(map[string]bool)
+	for _, images := range lotsImages {
+		for _, img := range images {
+			referencedSet[img] = true
+		}
+	}
+
+	storedSet := make(map[string]bool, len(storedImages))
+	deadline := time.Now().Add(-gracePeriod)
+
+	for _, img := range storedImages {
+		storedSet[img.Name] = true
+
+		if referencedSet[img.Name] {
+			continue
+		}
+
+		report.Orphans = append(report.Orphans, img.Name)
+
+		if img.ModifiedAt.After(deadline) {
+			report.Skipped = append(report.Skipped, img.Name)
+			continue
+		}
+
+		report.Deleted = append(report.Deleted, img.Name)
+	}
+
+	for lotID, images := range lotsImages {
+		for _, img := range images {
+			if !storedSet[img] {
+				report.Dangling[lotID] = append(report.Dangling[lotID], img)
+			}
+		}
+	}
+
+	if dryRun || len(report.Deleted) == 0 {
+		return report, nil
+	}
+
+	if err := s.DeleteImages(ctx, report.Deleted); err != nil {
+		logger.FromContext(ctx).Warn("Помилка видалення осиротілих зображень", "count", len(report.Deleted), "err", err.Error())
+		report.Deleted = nil
+		return report, err
+	}
+
+	return report, nil
+}
@@ -4,41 +4,83 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log/slog"
 	"lots-service/internal/domain"
+	"lots-service/internal/lib/imageurl"
+	"lots-service/internal/lib/logger"
+	"lots-service/internal/lib/metrics"
 	"mime/multipart"
 	"net/http"
+	"path"
 	"path/filepath"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type LotsService struct {
 	repo              domain.LotsRepository
 	storageServiceURL string
+	imageURLs         *imageurl.Builder
+	uploads           *imageurl.UploadSigner
 	httpClient        http.Client
 }
 
-func NewLotsService(repo domain.LotsRepository, storageServiceURL string) *LotsService {
+func NewLotsService(repo domain.LotsRepository, storageServiceURL string, imageURLs *imageurl.Builder, uploads *imageurl.UploadSigner) *LotsService {
 	return &LotsService{
 		repo:              repo,
 		storageServiceURL: storageServiceURL,
-		httpClient:        http.Client{Timeout: 10 * time.Second},
+		imageURLs:         imageURLs,
+		uploads:           uploads,
+		httpClient:        http.Client{Timeout: 10 * time.Second, Transport: storageTransport()},
 	}
 }
 
-func (s *LotsService) StorageRequest(requestURL string, requestBody io.Reader, contentType string) error {
-	req, err := http.NewRequest("POST", requestURL, requestBody)
+// fillLot доповнює лот полями, які не зберігаються в БД
+func (s *LotsService) fillLot(lot *domain.Lot) {
+	lot.SaleStatusCode = domain.SaleStatusCode(lot.SaleStatus)
+	lot.ImageURLs = s.imageURLs.URLs(lot.Images, lot.IsHidden)
+}
+
+func (s *LotsService) fillLots(lots *[]domain.Lot) {
+	if lots == nil {
+		return
+	}
+
+	for i := range *lots {
+		s.fillLot(&(*lots)[i])
+	}
+}
+
+// storageDo виконує запит до storage і записує його результат у метрики
+func (s *LotsService) storageDo(req *http.Request, operation string) (*http.Response, error) {
+	start := time.Now()
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		metrics.ObserveStorage(operation, 0, time.Since(start))
+		return nil, err
+	}
+
+	metrics.ObserveStorage(operation, resp.StatusCode, time.Since(start))
+	return resp, nil
+}
+
+func (s *LotsService) StorageRequest(ctx context.Context, requestURL string, requestBody io.Reader, contentType string) (err error) {
+	ctx, span := startSpan(ctx, "StorageRequest", attribute.String("storage.operation", path.Base(requestURL)))
+	defer func() { endSpan(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, requestBody)
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("Content-Type", contentType)
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.storageDo(req, path.Base(req.URL.Path))
 	if err != nil {
 		return fmt.Errorf("помилка запиту до storage: %v", err)
 	}
@@ -52,52 +94,104 @@ func (s *LotsService) StorageRequest(requestURL string, requestBody io.Reader, c
 	return nil
 }
 
-func (s *LotsService) SaveImages(files []*multipart.FileHeader) ([]string, error) {
-	var newReqBody bytes.Buffer
-	writer := multipart.NewWriter(&newReqBody)
+// SaveImages передає файли в storage потоково: кожен файл копіюється з тіла
+// вхідного запиту прямо в io.Pipe запиту до storage, тож пам'ять не залежить
+// від розміру файлів. Без файлів запит до storage не виконується.
+func (s *LotsService) SaveImages(ctx context.Context, files domain.ImageStream) (_ []string, err error) {
+	ctx, span := startSpan(ctx, "SaveImages")
+	defer func() { endSpan(span, err) }()
+
+	if files == nil {
+		return nil, nil
+	}
+
+	filename, content, err := files.Next()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
 
 	var generatedNames []string
+	var formErr error
+	written := make(chan struct{})
 
-	for _, fileHeader := range files {
-		file, err := fileHeader.Open()
-		if err != nil {
-			slog.Debug("Помилка відкриття файлу", "err", err.Error())
-			return nil, err
-		}
+	go func() {
+		defer close(written)
+		generatedNames, formErr = writeImagesForm(ctx, writer, files, filename, content)
+		pipeWriter.CloseWithError(formErr)
+	}()
+
+	requestURL := fmt.Sprintf("%s/api/storage/upload_images", s.storageServiceURL)
+	requestErr := s.StorageRequest(ctx, requestURL, pipeReader, writer.FormDataContentType())
+
+	// Розблоковує запис, якщо storage відповів, не дочитавши тіло
+	pipeReader.Close()
+	<-written
+
+	// Помилка читання вхідного запиту (обрив, ліміт тіла) важливіша
+	// за наслідкову помилку запиту до storage
+	if formErr != nil && !errors.Is(formErr, io.ErrClosedPipe) {
+		return nil, formErr
+	}
+	if requestErr != nil {
+		return nil, requestErr
+	}
+	// storage відповів успіхом, не дочитавши форму: частина файлів не збережена
+	if formErr != nil {
+		return nil, fmt.Errorf("storage відповів до завершення передачі зображень: %w", formErr)
+	}
+
+	span.SetAttributes(attribute.Int("images.count", len(generatedNames)))
+
+	return generatedNames, nil
+}
+
+// writeImagesForm пише в форму вже прочитаний перший файл і решту файлів з потоку
+func writeImagesForm(ctx context.Context, writer *multipart.Writer, files domain.ImageStream, filename string, content io.Reader) ([]string, error) {
+	var names []string
 
-		ext := filepath.Ext(fileHeader.Filename)
-		newFilename := fmt.Sprintf("%s%s", uuid.New().String(), ext)
-		generatedNames = append(generatedNames, newFilename)
+	for {
+		name := uuid.New().String() + filepath.Ext(filename)
 
-		part, err := writer.CreateFormFile("files", newFilename)
+		part, err := writer.CreateFormFile("files", name)
 		if err != nil {
-			file.Close()
-			slog.Debug("Помилка створення форми", "err", err.Error())
-			return nil, err
+			logger.FromContext(ctx).Debug("Помилка створення форми", "err", err.Error())
+			return names, err
 		}
 
-		if _, err := io.Copy(part, file); err != nil {
-			file.Close()
-			slog.Debug("Помилка копіювання файлу", "err", err.Error())
-			return nil, err
+		if _, err := io.Copy(part, content); err != nil {
+			logger.FromContext(ctx).Debug("Помилка копіювання файлу", "err", err.Error())
+			return names, err
 		}
+		names = append(names, name)
 
-		file.Close()
+		filename, content, err = files.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			logger.FromContext(ctx).Debug("Помилка читання файлу", "err", err.Error())
+			return names, err
+		}
 	}
 
 	err := writer.Close()
 	if err != nil {
-		slog.Debug("Помилка закриття writer", "err", err.Error())
-		return nil, err
+		logger.FromContext(ctx).Debug("Помилка закриття writer", "err", err.Error())
 	}
 
-	requestURL := fmt.Sprintf("%s/api/storage/upload_images", s.storageServiceURL)
-	s.StorageRequest(requestURL, &newReqBody, writer.FormDataContentType())
-
-	return generatedNames, nil
+	return names, err
 }
 
-func (s *LotsService) DeleteImages(filenames []string) error {
+func (s *LotsService) DeleteImages(ctx context.Context, filenames []string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteImages", attribute.Int("images.count", len(filenames)))
+	defer func() { endSpan(span, err) }()
+
 	if len(filenames) == 0 {
 		return nil
 	}
@@ -111,139 +205,392 @@ func (s *LotsService) DeleteImages(filenames []string) error {
 	}
 
 	requestURL := fmt.Sprintf("%s/api/storage/delete_images", s.storageServiceURL)
-	s.StorageRequest(requestURL, bytes.NewBuffer(payload), "application/json")
+
+	return s.StorageRequest(ctx, requestURL, bytes.NewBuffer(payload), "application/json")
+}
+
+func (s *LotsService) ListImages(ctx context.Context) ([]domain.StoredImage, error) {
+	requestURL := fmt.Sprintf("%s/api/storage/list_images", s.storageServiceURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.storageDo(req, "list_images")
+	if err != nil {
+		return nil, fmt.Errorf("помилка запиту до storage: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("storage повернув помилку: %d, %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	// JSON: {"images": [{"name": "a.jpg", "modified_at": "2025-01-01T00:00:00Z"}]}
+	var payload struct {
+		Images []domain.StoredImage `json:"images"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("помилка декодування відповіді storage: %w", err)
+	}
+
+	return payload.Images, nil
+}
+
+// PingStorage перевіряє, що storage відповідає. Будь-яка відповідь без 5xx
+// означає, що сервіс доступний.
+func (s *LotsService) PingStorage(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.storageServiceURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.storageDo(req, "ping")
+	if err != nil {
+		return fmt.Errorf("помилка запиту до storage: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("storage повернув помилку: %d", resp.StatusCode)
+	}
 
 	return nil
 }
 
-func (s *LotsService) GetLotsCount() (int, error) {
-	return s.repo.GetLotsCount()
+// CheckImages повертає ті з filenames, які є у сховищі
+func (s *LotsService) CheckImages(ctx context.Context, filenames []string) ([]string, error) {
+	if len(filenames) == 0 {
+		return nil, nil
+	}
+
+	payload, err := json.Marshal(map[string][]string{
+		"filenames": filenames,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	requestURL := fmt.Sprintf("%s/api/storage/check_images", s.storageServiceURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.storageDo(req, "check_images")
+	if err != nil {
+		return nil, fmt.Errorf("помилка запиту до storage: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("storage повернув помилку: %d, %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	// JSON: {"existing": ["a.jpg"]}
+	var result struct {
+		Existing []string `json:"existing"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("помилка декодування відповіді storage: %w", err)
+	}
+
+	return result.Existing, nil
 }
 
-func (s *LotsService) GetLotsByParamsCount(brand, model, minPrice, maxPrice, minYear, maxYear string) (int, error) {
-	return s.repo.GetLotsByParamsCount(brand, model, minPrice, maxPrice, minYear, maxYear)
+func (s *LotsService) GetLotsCount(ctx context.Context) (int, error) {
+	return s.repo.GetLotsCount(ctx)
 }
 
-func (s *LotsService) GetLotByID(userID, lotID int) (*domain.Lot, error) {
-	return s.repo.GetLotByID(userID, lotID)
+func (s *LotsService) GetLotsByParamsCount(ctx context.Context, brand, model, minPrice, maxPrice, minYear, maxYear string) (int, error) {
+	return s.repo.GetLotsByParamsCount(ctx, brand, model, minPrice, maxPrice, minYear, maxYear)
 }
 
-func (s *LotsService) GetPageLots(userID, page, limit int) (*[]domain.Lot, error) {
-	lots, _, err := s.repo.GetLotsByParams(userID, page, limit, "", "", "", "", "", "")
-	return lots, err
+func (s *LotsService) GetLotByID(ctx context.Context, userID, lotID int) (_ *domain.Lot, err error) {
+	ctx, span := startSpan(ctx, "GetLotByID", attribute.Int("lot.id", lotID))
+	defer func() { endSpan(span, err) }()
+
+	lot, err := s.repo.GetLotByID(ctx, userID, lotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if lot.IsHidden && lot.SellerID != userID {
+		return nil, domain.NewNotFound(domain.CodeLotNotFound, fmt.Sprintf("лот %d приховано", lotID))
+	}
+
+	s.fillLot(lot)
+
+	return lot, nil
 }
 
-func (s *LotsService) GetLotsByParams(userID int, page, limit int, brand, model, minPrice, maxPrice, minYear, maxYear string) (*[]domain.Lot, int, error) {
-	return s.repo.GetLotsByParams(userID, page, limit, brand, model, minPrice, maxPrice, minYear, maxYear)
+func (s *LotsService) GetPageLots(ctx context.Context, userID, page, limit int) (_ *[]domain.Lot, err error) {
+	ctx, span := startSpan(ctx, "GetPageLots", attribute.Int("page", page), attribute.Int("limit", limit))
+	defer func() { endSpan(span, err) }()
+
+	lots, _, err := s.repo.GetLotsByParams(ctx, userID, page, limit, "", "", "", "", "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	s.fillLots(lots)
+
+	return lots, nil
 }
 
-func (s *LotsService) GetBrands() (*[]domain.Brand, error) {
-	return s.repo.GetBrands()
+func (s *LotsService) GetLotsByParams(ctx context.Context, userID int, page, limit int, brand, model, minPrice, maxPrice, minYear, maxYear string) (_ *[]domain.Lot, _ int, err error) {
+	ctx, span := startSpan(ctx, "GetLotsByParams", attribute.Int("page", page), attribute.Int("limit", limit))
+	defer func() { endSpan(span, err) }()
+
+	lots, total, err := s.repo.GetLotsByParams(ctx, userID, page, limit, brand, model, minPrice, maxPrice, minYear, maxYear)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.fillLots(lots)
+
+	return lots, total, nil
 }
 
-func (s *LotsService) GetModels(brandName string) (*[]domain.Model, error) {
-	return s.repo.GetModels(brandName)
+func (s *LotsService) GetBrands(ctx context.Context) (*[]domain.Brand, error) {
+	return s.repo.GetBrands(ctx)
 }
 
-func (s *LotsService) GetUserPostedLots(userID int) (*[]domain.Lot, error) {
-	return s.repo.GetUserPostedLots(userID)
+func (s *LotsService) GetModels(ctx context.Context, brandName string) (*[]domain.Model, error) {
+	return s.repo.GetModels(ctx, brandName)
 }
 
-func (s *LotsService) GetUserLikedLots(userID int) (*[]domain.Lot, error) {
-	return s.repo.GetUserLikedLots(userID)
+func (s *LotsService) GetUserPostedLots(ctx context.Context, userID int) (_ *[]domain.Lot, err error) {
+	ctx, span := startSpan(ctx, "GetUserPostedLots", attribute.Int("user.id", userID))
+	defer func() { endSpan(span, err) }()
+
+	lots, err := s.repo.GetUserPostedLots(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.fillLots(lots)
+
+	return lots, nil
 }
 
-func (s *LotsService) CreateLot(ctx context.Context, lot *domain.Lot, files []*multipart.FileHeader) error {
-	if len(files) > 0 {
-		images, err := s.SaveImages(files)
-		if err != nil {
-			return fmt.Errorf("помилка збереження зображень: %w", err)
-		}
-		lot.Images = images
+func (s *LotsService) GetUserLikedLots(ctx context.Context, userID int) (_ *[]domain.Lot, err error) {
+	ctx, span := startSpan(ctx, "GetUserLikedLots", attribute.Int("user.id", userID))
+	defer func() { endSpan(span, err) }()
+
+	lots, err := s.repo.GetUserLikedLots(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.fillLots(lots)
+
+	return lots, nil
+}
+
+func (s *LotsService) CreateLot(ctx context.Context, lot *domain.Lot, files domain.ImageStream, uploaded domain.UploadedImages) (err error) {
+	ctx, span := startSpan(ctx, "CreateLot", attribute.Int("user.id", lot.SellerID))
+	defer func() { endSpan(span, err) }()
+
+	uploads, err := s.confirmUploadedImages(ctx, lot.SellerID, uploaded)
+	if err != nil {
+		return err
+	}
+
+	images, err := s.SaveImages(ctx, files)
+	if err != nil {
+		return fmt.Errorf("помилка збереження зображень: %w", err)
+	}
+	if uploads != nil {
+		images = append(images, uploads.ImageIDs...)
+	}
+	lot.Images = images
+
+	if err := s.repo.CreateLot(ctx, lot, uploads); err != nil {
+		return err
 	}
 
-	return s.repo.CreateLot(ctx, lot)
+	metrics.LotCreated()
+	return nil
 }
 
-func (s *LotsService) UpdateLot(ctx context.Context, lot *domain.Lot, newFiles []*multipart.FileHeader, deleteImages []string, oldImages []string) error {
-	existingLot, err := s.repo.GetLotByID(lot.SellerID, lot.LotID)
+func (s *LotsService) UpdateLot(ctx context.Context, lot *domain.Lot, newFiles domain.ImageStream, uploaded domain.UploadedImages, deleteImages []string, oldImages []string) (err error) {
+	ctx, span := startSpan(ctx, "UpdateLot", attribute.Int("lot.id", lot.LotID))
+	defer func() { endSpan(span, err) }()
+
+	existingLot, err := s.repo.GetLotByID(ctx, lot.SellerID, lot.LotID)
 	if err != nil {
 		return err
 	}
 
 	if existingLot.SellerID != lot.SellerID {
-		return fmt.Errorf("sellerID не співпадає з userID")
+		return domain.NewForbidden(domain.CodeNotLotOwner, "sellerID не співпадає з userID")
 	}
 
-	if len(deleteImages) > 0 {
-		if err := s.DeleteImages(deleteImages); err != nil {
-			slog.Warn("Помилка видалення зображень", "err", err.Error())
-		}
+	return s.applyLotUpdate(ctx, lot.SellerID, existingLot, lot, newFiles, uploaded, deleteImages, oldImages, nil)
+}
+
+// applyLotUpdate зберігає зміни лота. actorID — користувач, від імені якого
+// підтверджуються завантажені зображення (продавець або адміністратор),
+// audit — запис журналу для дії адміністратора.
+func (s *LotsService) applyLotUpdate(ctx context.Context, actorID int, existingLot, lot *domain.Lot, newFiles domain.ImageStream, uploaded domain.UploadedImages, deleteImages []string, oldImages []string, audit *domain.AdminAction) error {
+	// Набір зображень визначається тільки з того, що вже збережено в лоті,
+	// імена від клієнта лише перевіряються на належність
+	ownedSet := make(map[string]bool, len(existingLot.Images))
+	for _, img := range existingLot.Images {
+		ownedSet[img] = true
 	}
 
-	var newImageNames []string
-	if len(newFiles) > 0 {
-		newImageNames, err = s.SaveImages(newFiles)
-		if err != nil {
-			return err
-		}
+	if err := checkImagesOwnership(ownedSet, oldImages, lot.LotID); err != nil {
+		return err
+	}
+	if err := checkImagesOwnership(ownedSet, deleteImages, lot.LotID); err != nil {
+		return err
 	}
 
-	deletedSet := make(map[string]bool)
+	uploads, err := s.confirmUploadedImages(ctx, actorID, uploaded)
+	if err != nil {
+		return err
+	}
+	var confirmedImages []string
+	if uploads != nil {
+		confirmedImages = uploads.ImageIDs
+	}
+
+	deletedSet := make(map[string]bool, len(deleteImages))
 	for _, img := range deleteImages {
 		deletedSet[img] = true
 	}
 
+	var newImageNames []string
+	if newFiles != nil {
+		newImageNames, err = s.SaveImages(ctx, newFiles)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Одне зображення не може двічі потрапити в лот
+	seen := make(map[string]bool, len(existingLot.Images)+len(newImageNames)+len(confirmedImages))
 	var finalImages []string
-	for _, img := range oldImages {
-		if !deletedSet[img] {
+	for _, images := range [][]string{existingLot.Images, newImageNames, confirmedImages} {
+		for _, img := range images {
+			if deletedSet[img] || seen[img] {
+				continue
+			}
+			seen[img] = true
 			finalImages = append(finalImages, img)
 		}
 	}
-	finalImages = append(finalImages, newImageNames...)
 
 	lot.Images = finalImages
 
-	return s.repo.UpdateLot(ctx, lot)
+	if err := s.repo.UpdateLot(ctx, lot, uploads, audit); err != nil {
+		return err
+	}
+
+	// Як і при видаленні лота: спершу БД, потім сховище. Зображення, які не
+	// вдалося прибрати, підбере задача узгодження сховища.
+	if len(deletedSet) > 0 {
+		if err := s.DeleteImages(ctx, deleteImages); err != nil {
+			logger.FromContext(ctx).Warn("Помилка видалення зображень", "lotID", lot.LotID, "err", err.Error())
+		}
+	}
+
+	return nil
 }
 
-func (s *LotsService) DeleteLot(ctx context.Context, lotID, userID int) error {
-	lot, err := s.repo.GetLotByID(userID, lotID)
+func checkImagesOwnership(ownedSet map[string]bool, images []string, lotID int) error {
+	for _, img := range images {
+		if !ownedSet[img] {
+			return domain.NewForbidden(domain.CodeImageNotOwned, fmt.Sprintf("зображення %q, лот %d", img, lotID))
+		}
+	}
+
+	return nil
+}
+
+func (s *LotsService) DeleteLot(ctx context.Context, lotID, userID int) (err error) {
+	ctx, span := startSpan(ctx, "DeleteLot", attribute.Int("lot.id", lotID))
+	defer func() { endSpan(span, err) }()
+
+	lot, err := s.repo.GetLotByID(ctx, userID, lotID)
 	if err != nil {
 		return err
 	}
 	if lot.SellerID != userID {
-		return fmt.Errorf("sellerID не співпадає з userID")
+		return domain.NewForbidden(domain.CodeNotLotOwner, "sellerID не співпадає з userID")
 	}
 
-	if err := s.repo.DeleteLot(ctx, lotID); err != nil {
+	return s.deleteLot(ctx, lot, nil)
+}
+
+func (s *LotsService) deleteLot(ctx context.Context, lot *domain.Lot, audit *domain.AdminAction) error {
+	lotID := lot.LotID
+
+	if err := s.repo.DeleteLot(ctx, lotID, audit); err != nil {
 		return err
 	}
 
-	if err := s.DeleteImages(lot.Images); err != nil {
-		slog.Warn("Помилка очистки зображень", "lotID", lotID, "err", err.Error())
-		return err
+	// Лот вже видалено, тож зображення, які не вдалося прибрати,
+	// підбере задача узгодження сховища
+	if err := s.DeleteImages(ctx, lot.Images); err != nil {
+		logger.FromContext(ctx).Warn("Помилка очистки зображень", "lotID", lotID, "err", err.Error())
 	}
 
 	return nil
 }
 
-func (s *LotsService) LikeLot(userID, lotID int) error {
-	return s.repo.LikeLot(userID, lotID)
+func (s *LotsService) LikeLot(ctx context.Context, userID, lotID int) (err error) {
+	ctx, span := startSpan(ctx, "LikeLot", attribute.Int("lot.id", lotID))
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.LikeLot(ctx, userID, lotID); err != nil {
+		return err
+	}
+
+	metrics.LotLiked(true)
+	return nil
 }
 
-func (s *LotsService) UnlikeLot(userID, lotID int) error {
-	return s.repo.UnlikeLot(userID, lotID)
+func (s *LotsService) UnlikeLot(ctx context.Context, userID, lotID int) (err error) {
+	ctx, span := startSpan(ctx, "UnlikeLot", attribute.Int("lot.id", lotID))
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.UnlikeLot(ctx, userID, lotID); err != nil {
+		return err
+	}
+
+	metrics.LotLiked(false)
+	return nil
 }
 
-func (s *LotsService) BuyLot(userID, lotID int) error {
-	lot, err := s.repo.GetLotByID(userID, lotID)
-	if err != nil {
+func (s *LotsService) BuyLot(ctx context.Context, userID, lotID int) (err error) {
+	ctx, span := startSpan(ctx, "BuyLot", attribute.Int("lot.id", lotID))
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.MarkLotAsSold(ctx, lotID, true); err != nil {
 		return err
 	}
-	if lot.SaleStatus == "Продано" {
-		return fmt.Errorf("лот вже продано")
+
+	metrics.LotSold(metrics.SoldByBuyer)
+	return nil
+}
+
+// MarkLotSold позначає лот проданим за запитом внутрішнього сервісу (наприклад, після оплати)
+func (s *LotsService) MarkLotSold(ctx context.Context, lotID int) (err error) {
+	ctx, span := startSpan(ctx, "MarkLotSold", attribute.Int("lot.id", lotID))
+	defer func() { endSpan(span, err) }()
+
+	if err := s.repo.MarkLotAsSold(ctx, lotID, false); err != nil {
+		return err
 	}
 
-	return s.repo.MarkLotAsSold(lotID)
+	metrics.LotSold(metrics.SoldByInternal)
+	return nil
 }
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"lots-service/internal/domain"
+)
+
+// fakeLotsRepo підміняє лише методи, потрібні для оновлення лота;
+// виклик будь-якого іншого методу вбудованого nil-інтерфейсу падає
+type fakeLotsRepo struct {
+	domain.LotsRepository
+
+	lot       *domain.Lot
+	updateErr error
+
+	updated *domain.Lot
+	audit   *domain.AdminAction
+}
+
+func (r *fakeLotsRepo) GetLotByID(_ context.Context, _, lotID int) (*domain.Lot, error) {
+	if r.lot == nil || r.lot.LotID != lotID {
+		return nil, domain.NewNotFound(domain.CodeLotNotFound, "")
+	}
+	lot := *r.lot
+	return &lot, nil
+}
+
+func (r *fakeLotsRepo) UpdateLot(_ context.Context, lot *domain.Lot, _ *domain.UploadClaim, audit *domain.AdminAction) error {
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	r.updated, r.audit = lot, audit
+	return nil
+}
+
+// fakeStorage запам'ятовує, які операції storage викликав сервіс
+type fakeStorage struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func newFakeStorage(t *testing.T) *fakeStorage {
+	t.Helper()
+
+	storage := &fakeStorage{}
+	storage.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		storage.mu.Lock()
+		storage.calls = append(storage.calls, r.URL.Path)
+		storage.mu.Unlock()
+	}))
+	t.Cleanup(storage.Close)
+
+	return storage
+}
+
+func (s *fakeStorage) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.calls...)
+}
+
+const (
+	sellerID = 7
+	adminID  = 1
+	lotID    = 42
+)
+
+func sellerLot() *domain.Lot {
+	return &domain.Lot{LotID: lotID, SellerID: sellerID, Images: []string{"old.jpg", "keep.jpg"}}
+}
+
+func TestUpdateLotRejectsNonOwner(t *testing.T) {
+	repo := &fakeLotsRepo{lot: sellerLot()}
+	storage := newFakeStorage(t)
+	s := NewLotsService(repo, storage.URL, nil, nil)
+
+	err := s.UpdateLot(context.Background(), &domain.Lot{LotID: lotID, SellerID: 99}, nil, domain.UploadedImages{}, []string{"old.jpg"}, nil)
+
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || !errors.Is(err, domain.ErrForbidden) || domainErr.Code != domain.CodeNotLotOwner {
+		t.Fatalf("err = %v, want forbidden not_lot_owner", err)
+	}
+	if repo.updated != nil {
+		t.Fatalf("lot was updated by a non-owner")
+	}
+	if calls := storage.Calls(); len(calls) != 0 {
+		t.Fatalf("storage calls = %v, want none", calls)
+	}
+}
+
+func TestAdminUpdateLotBypassesOwnership(t *testing.T) {
+	repo := &fakeLotsRepo{lot: sellerLot()}
+	storage := newFakeStorage(t)
+	s := NewLotsService(repo, storage.URL, nil, nil)
+
+	err := s.AdminUpdateLot(context.Background(), adminID, &domain.Lot{LotID: lotID, SellerID: adminID}, nil, domain.UploadedImages{}, []string{"old.jpg"}, nil, "spam")
+	if err != nil {
+		t.Fatalf("AdminUpdateLot: %v", err)
+	}
+
+	if repo.updated == nil || repo.updated.SellerID != sellerID {
+		t.Fatalf("updated lot = %+v, want seller %d kept", repo.updated, sellerID)
+	}
+	if repo.audit == nil || repo.audit.AdminID != adminID || repo.audit.Action != domain.AdminActionUpdateLot {
+		t.Fatalf("audit = %+v, want update_lot by admin %d", repo.audit, adminID)
+	}
+	if images := repo.updated.Images; len(images) != 1 || images[0] != "keep.jpg" {
+		t.Fatalf("images = %v, want [keep.jpg]", images)
+	}
+	if calls := storage.Calls(); len(calls) != 1 || calls[0] != "/api/storage/delete_images" {
+		t.Fatalf("storage calls = %v, want one delete_images", calls)
+	}
+}
+
+func TestFailedUpdateKeepsStoredImages(t *testing.T) {
+	repo := &fakeLotsRepo{lot: sellerLot(), updateErr: domain.NewUnavailable(domain.CodeTimeout, "")}
+	storage := newFakeStorage(t)
+	s := NewLotsService(repo, storage.URL, nil, nil)
+
+	err := s.UpdateLot(context.Background(), &domain.Lot{LotID: lotID, SellerID: sellerID}, nil, domain.UploadedImages{}, []string{"old.jpg"}, nil)
+	if !errors.Is(err, domain.ErrUnavailable) {
+		t.Fatalf("err = %v, want the repository error", err)
+	}
+
+	// Лот досі посилається на old.jpg, тож файл у сховищі лишається
+	if calls := storage.Calls(); len(calls) != 0 {
+		t.Fatalf("storage calls = %v, want none after a failed update", calls)
+	}
+}
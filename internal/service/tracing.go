@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"lots-service/internal/lib/tracing"
+	"net/http"
+	"path"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan відкриває спан методу сервісу. Спан закривається через endSpan,
+// щоб помилка методу потрапила в трасу.
+func startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "LotsService."+method, attrs...)
+}
+
+func endSpan(span trace.Span, err error) {
+	tracing.Fail(span, err)
+	span.End()
+}
+
+// storageTransport відкриває клієнтський спан на кожен запит до storage
+// і передає W3C trace context у заголовку traceparent
+func storageTransport() http.RoundTripper {
+	return otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return "storage " + path.Base(r.URL.Path)
+		}),
+	)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"lots-service/internal/domain"
+	"lots-service/internal/lib/imageurl"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const maxUploadSlots = 20
+
+var errUploadsDisabled = domain.NewUnavailable(domain.CodeUploadsDisabled, "не задано STORAGE_SIGNING_SECRET")
+
+var allowedImageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".webp": true,
+}
+
+// CreateUploadSession видає слоти для завантаження зображень напряму в storage,
+// по одному на кожне розширення з extensions
+func (s *LotsService) CreateUploadSession(ctx context.Context, userID int, extensions []string) (_ *domain.UploadSessionResponse, err error) {
+	_, span := startSpan(ctx, "CreateUploadSession", attribute.Int("user.id", userID))
+	defer func() { endSpan(span, err) }()
+
+	if !s.uploads.Enabled() {
+		return nil, errUploadsDisabled
+	}
+
+	if len(extensions) == 0 || len(extensions) > maxUploadSlots {
+		return nil, invalidUpload("extensions", fmt.Sprintf("кількість слотів має бути від 1 до %d", maxUploadSlots))
+	}
+
+	expiresAt := time.Now().Add(s.uploads.TTL())
+	session := imageurl.UploadSession{
+		UserID:    userID,
+		ExpiresAt: expiresAt.Unix(),
+	}
+
+	var slots []domain.UploadSlot
+
+	for _, ext := range extensions {
+		ext = strings.ToLower(ext)
+		if !allowedImageExtensions[ext] {
+			return nil, invalidUpload("extensions", fmt.Sprintf("недопустиме розширення %q", ext))
+		}
+
+		imageID := uuid.New().String() + ext
+		session.ImageIDs = append(session.ImageIDs, imageID)
+		slots = append(slots, domain.UploadSlot{
+			ImageID:   imageID,
+			UploadURL: s.uploads.UploadURL(imageID, expiresAt),
+		})
+	}
+
+	token, err := s.uploads.SessionToken(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UploadSessionResponse{
+		SessionToken: token,
+		ExpiresAt:    expiresAt,
+		Slots:        slots,
+	}, nil
+}
+
+// confirmUploadedImages перевіряє, що зображення видані цьому користувачу
+// в межах чинної сесії і дійсно з'явилися у сховищі. Токен сесії діє до
+// завершення TTL, тож репозиторій позначає зображення використаними разом
+// із записом лота, і вони не можуть потрапити в другий лот.
+func (s *LotsService) confirmUploadedImages(ctx context.Context, userID int, uploaded domain.UploadedImages) (*domain.UploadClaim, error) {
+	if len(uploaded.ImageIDs) == 0 {
+		return nil, nil
+	}
+
+	if !s.uploads.Enabled() {
+		return nil, errUploadsDisabled
+	}
+
+	session, err := s.uploads.ParseSessionToken(uploaded.SessionToken, time.Now())
+	if err != nil {
+		return nil, invalidUpload("UploadSessionToken", err.Error())
+	}
+
+	if session.UserID != userID {
+		return nil, invalidUpload("UploadSessionToken", "сесія належить іншому користувачу")
+	}
+
+	issuedSet := make(map[string]bool, len(session.ImageIDs))
+	for _, id := range session.ImageIDs {
+		issuedSet[id] = true
+	}
+
+	confirmedSet := make(map[string]bool, len(uploaded.ImageIDs))
+	var confirmed []string
+
+	for _, id := range uploaded.ImageIDs {
+		if !issuedSet[id] {
+			return nil, invalidUpload("UploadedImages", fmt.Sprintf("зображення %q не видавалося в цій сесії", id))
+		}
+		if confirmedSet[id] {
+			continue
+		}
+
+		confirmedSet[id] = true
+		confirmed = append(confirmed, id)
+	}
+
+	existing, err := s.CheckImages(ctx, confirmed)
+	if err != nil {
+		return nil, err
+	}
+
+	existingSet := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		existingSet[id] = true
+	}
+
+	for _, id := range confirmed {
+		if !existingSet[id] {
+			return nil, invalidUpload("UploadedImages", fmt.Sprintf("зображення %q не завантажене в сховище", id))
+		}
+	}
+
+	return &domain.UploadClaim{UserID: userID, ImageIDs: confirmed}, nil
+}
+
+func invalidUpload(field, detail string) *domain.Error {
+	err := domain.NewValidation(domain.CodeInvalidUpload, map[string]string{field: detail})
+	err.Detail = detail
+	return err
+}
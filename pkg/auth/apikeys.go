@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+const (
+	ScopeLotsRead     = "lots:read"
+	ScopeLotsMarkSold = "lots:mark_sold"
+)
+
+// ServiceKey — ключ доступу внутрішнього сервісу (payments, notifications тощо)
+type ServiceKey struct {
+	Name   string
+	Key    string
+	Scopes []string
+}
+
+// ServiceCaller — внутрішній сервіс, що автентифікувався API-ключем
+type ServiceCaller struct {
+	Name   string
+	Scopes []string
+}
+
+type serviceCallerKey struct{}
+
+func ServiceCallerFromContext(ctx context.Context) (*ServiceCaller, bool) {
+	caller, ok := ctx.Value(serviceCallerKey{}).(*ServiceCaller)
+	return caller, ok && caller != nil
+}
+
+type APIKeyAuthenticator struct {
+	// Ключі зберігаються за SHA-256, щоб не тримати в мапі сирі секрети
+	callers map[[sha256.Size]byte]*ServiceCaller
+	onError ErrorWriter
+}
+
+// onError форматує відповідь на відмову, nil — WriteJSONError
+func NewAPIKeyAuthenticator(keys []ServiceKey, onError ErrorWriter) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{
+		callers: make(map[[sha256.Size]byte]*ServiceCaller, len(keys)),
+		onError: errorWriterOrDefault(onError),
+	}
+
+	for _, key := range keys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("API key for service %q is empty", key.Name)
+		}
+
+		hash := sha256.Sum256([]byte(key.Key))
+		if _, exists := a.callers[hash]; exists {
+			return nil, fmt.Errorf("API key for service %q is duplicated", key.Name)
+		}
+
+		a.callers[hash] = &ServiceCaller{Name: key.Name, Scopes: key.Scopes}
+	}
+
+	return a, nil
+}
+
+// RequireScope пропускає тільки запити з API-ключем, якому дозволено scope
+func (a *APIKeyAuthenticator) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				a.onError(w, r, NewError(ErrMissingAPIKey))
+				return
+			}
+
+			caller, ok := a.callers[sha256.Sum256([]byte(key))]
+			if !ok {
+				a.onError(w, r, NewError(ErrInvalidAPIKey))
+				return
+			}
+
+			if !slices.Contains(caller.Scopes, scope) {
+				a.onError(w, r, NewError(fmt.Errorf("%w: service %q has no scope %q", ErrForbidden, caller.Name, scope)))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), serviceCallerKey{}, caller)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrMissingToken   = errors.New("missing token")
+	ErrMalformedToken = errors.New("malformed token")
+	ErrInvalidToken   = errors.New("invalid token")
+	ErrTokenExpired   = errors.New("token expired")
+	ErrTokenRevoked   = errors.New("token revoked")
+	ErrForbidden      = errors.New("forbidden")
+	ErrMissingAPIKey  = errors.New("missing API key")
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+
+	ErrRevocationUnavailable = errors.New("revocation check unavailable")
+)
+
+// Стабільні коди відмов авторизації, які клієнт отримує в полі error_code
+const (
+	CodeMissingToken    = "missing_token"
+	CodeMalformedToken  = "malformed_token"
+	CodeInvalidToken    = "invalid_token"
+	CodeTokenExpired    = "token_expired"
+	CodeTokenRevoked    = "token_revoked"
+	CodeForbidden       = "forbidden"
+	CodeMissingAPIKey   = "missing_api_key"
+	CodeInvalidAPIKey   = "invalid_api_key"
+	CodeAuthUnavailable = "auth_unavailable"
+)
+
+// Error — відмова авторизації з HTTP-статусом і кодом. Пакет не знає, як
+// сервіс форматує відповіді, тому тіло відповіді пише ErrorWriter.
+type Error struct {
+	Status int
+	Code   string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewError визначає статус і код за помилкою перевірки токена чи API-ключа
+func NewError(err error) *Error {
+	status, code := http.StatusUnauthorized, CodeInvalidToken
+
+	switch {
+	case errors.Is(err, ErrMissingToken):
+		code = CodeMissingToken
+	case errors.Is(err, ErrMalformedToken):
+		code = CodeMalformedToken
+	case errors.Is(err, ErrTokenExpired):
+		code = CodeTokenExpired
+	case errors.Is(err, ErrTokenRevoked):
+		code = CodeTokenRevoked
+	case errors.Is(err, ErrMissingAPIKey):
+		code = CodeMissingAPIKey
+	case errors.Is(err, ErrInvalidAPIKey):
+		code = CodeInvalidAPIKey
+	case errors.Is(err, ErrForbidden):
+		status, code = http.StatusForbidden, CodeForbidden
+	case errors.Is(err, ErrRevocationUnavailable):
+		status, code = http.StatusServiceUnavailable, CodeAuthUnavailable
+	}
+
+	return &Error{Status: status, Code: code, Err: err}
+}
+
+// ErrorWriter відповідає клієнту на відмову авторизації. Заголовок
+// WWW-Authenticate на момент виклику вже встановлено.
+type ErrorWriter func(w http.ResponseWriter, r *http.Request, err *Error)
+
+type errorResponse struct {
+	Code      int    `json:"code"`
+	ErrorCode string `json:"error_code"`
+}
+
+// WriteJSONError — ErrorWriter за замовчуванням, без локалізованого повідомлення
+func WriteJSONError(w http.ResponseWriter, _ *http.Request, err *Error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(err.Status)
+
+	_ = json.NewEncoder(w).Encode(errorResponse{Code: err.Status, ErrorCode: err.Code})
+}
+
+func errorWriterOrDefault(onError ErrorWriter) ErrorWriter {
+	if onError == nil {
+		return WriteJSONError
+	}
+
+	return onError
+}
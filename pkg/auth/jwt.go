@@ -1,32 +1,180 @@
 package auth
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"net/http"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
-var jwtKey = []byte(os.Getenv("JWT_SECRET"))
+type Options struct {
+	// HS256 (спільний Secret) або RS256 (публічні ключі з JWKSURL чи PublicKeyPath)
+	Algorithm           string
+	Secret              []byte
+	PublicKeyPath       string
+	JWKSURL             string
+	KeysRefreshInterval time.Duration
+	Issuer              string
+	Audience            string
+	ClockSkew           time.Duration
+	// Необов'язкова перевірка відкликаних токенів
+	Revocations RevocationChecker
+	// Форматує відповідь на відмову, nil — WriteJSONError
+	ErrorWriter ErrorWriter
+}
 
-func UserIDFromToken(tokenString string) (int, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-		return jwtKey, nil
-	})
+type Authenticator struct {
+	algorithm   string
+	keys        *keyStore
+	issuer      string
+	audience    string
+	clockSkew   time.Duration
+	parser      *jwt.Parser
+	revocations RevocationChecker
+	onError     ErrorWriter
+}
 
-	if err != nil || !token.Valid {
-		return 0, fmt.Errorf("invalid token")
+func NewAuthenticator(opts Options) (*Authenticator, error) {
+	a := &Authenticator{
+		algorithm:   opts.Algorithm,
+		issuer:      opts.Issuer,
+		audience:    opts.Audience,
+		clockSkew:   opts.ClockSkew,
+		revocations: opts.Revocations,
+		onError:     errorWriterOrDefault(opts.ErrorWriter),
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return 0, fmt.Errorf("cannot parse token claims")
+	var load keysLoader
+	var refreshInterval time.Duration
+
+	switch opts.Algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if len(opts.Secret) == 0 {
+			return nil, fmt.Errorf("JWT secret is not set for %s", opts.Algorithm)
+		}
+		load = secretKeys(opts.Secret)
+	case jwt.SigningMethodRS256.Alg():
+		switch {
+		case opts.JWKSURL != "":
+			load = jwksKeys(&http.Client{Timeout: 10 * time.Second}, opts.JWKSURL)
+		case opts.PublicKeyPath != "":
+			load = pemKeys(opts.PublicKeyPath)
+		default:
+			return nil, fmt.Errorf("neither JWKS URL nor public key path is set for %s", opts.Algorithm)
+		}
+		refreshInterval = opts.KeysRefreshInterval
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm: %q", opts.Algorithm)
+	}
+
+	keys, err := newKeyStore(load, refreshInterval)
+	if err != nil {
+		return nil, err
+	}
+	a.keys = keys
+
+	// Стандартна перевірка claims у v4 не має допуску на розбіжність годинників,
+	// тому exp/nbf/iss/aud перевіряються вручну в validateClaims
+	a.parser = jwt.NewParser(
+		jwt.WithValidMethods([]string{opts.Algorithm}),
+		jwt.WithoutClaimsValidation(),
+	)
+
+	return a, nil
+}
+
+func (a *Authenticator) UserIDFromToken(tokenString string) (int, error) {
+	principal, err := a.PrincipalFromToken(tokenString)
+	if err != nil {
+		return 0, err
+	}
+
+	return principal.UserID, nil
+}
+
+func (a *Authenticator) PrincipalFromToken(tokenString string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+
+	token, err := a.parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return a.keys.key(kid)
+	})
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) && validationErr.Errors&jwt.ValidationErrorMalformed != 0 {
+			return nil, ErrMalformedToken
+		}
+		return nil, ErrInvalidToken
+	}
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	if err := a.validateClaims(claims, time.Now()); err != nil {
+		return nil, err
 	}
 
 	userIDFloat, ok := claims["user_id"].(float64)
 	if !ok {
-		return 0, fmt.Errorf("user_id not found in token")
+		return nil, fmt.Errorf("%w: user_id not found in token", ErrInvalidToken)
+	}
+
+	principal := &Principal{UserID: int(userIDFloat)}
+	principal.TokenID, _ = claims["jti"].(string)
+	principal.ExpiresAt = claimTime(claims, "exp")
+	principal.IssuedAt = claimTime(claims, "iat")
+
+	if roles, ok := claims["roles"].([]any); ok {
+		for _, role := range roles {
+			if roleStr, ok := role.(string); ok {
+				principal.Roles = append(principal.Roles, roleStr)
+			}
+		}
+	}
+
+	return principal, nil
+}
+
+func claimTime(claims jwt.MapClaims, name string) time.Time {
+	switch value := claims[name].(type) {
+	case float64:
+		return time.Unix(int64(value), 0)
+	case json.Number:
+		seconds, _ := value.Int64()
+		return time.Unix(seconds, 0)
+	}
+
+	return time.Time{}
+}
+
+// Close зупиняє періодичне оновлення ключів
+func (a *Authenticator) Close() {
+	a.keys.close()
+}
+
+func (a *Authenticator) validateClaims(claims jwt.MapClaims, now time.Time) error {
+	if _, ok := claims["exp"]; !ok {
+		return fmt.Errorf("%w: exp is missing", ErrInvalidToken)
+	}
+
+	if !claims.VerifyExpiresAt(now.Add(-a.clockSkew).Unix(), true) {
+		return ErrTokenExpired
+	}
+
+	if !claims.VerifyNotBefore(now.Add(a.clockSkew).Unix(), false) {
+		return fmt.Errorf("%w: token is not valid yet", ErrInvalidToken)
+	}
+
+	if a.issuer != "" && !claims.VerifyIssuer(a.issuer, true) {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+
+	if a.audience != "" && !claims.VerifyAudience(a.audience, true) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
 	}
 
-	return int(userIDFloat), nil
+	return nil
 }
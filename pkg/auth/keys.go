@@ -0,0 +1,280 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Мінімальний інтервал між позаплановими перезавантаженнями ключів,
+// щоб токени з вигаданим kid не перетворювали сервіс на DDoS для JWKS
+const minKeysReloadInterval = 30 * time.Second
+
+type keysLoader func() (map[string]any, error)
+
+// keyStore зберігає ключі перевірки за kid і періодично їх оновлює,
+// тож сервіс авторизації може ротувати ключі без перезапуску lots
+type keyStore struct {
+	mu   sync.RWMutex
+	keys map[string]any
+	load keysLoader
+	// reloadMu серіалізує перезавантаження: одночасні запити з невідомим kid
+	// чекають на одне звернення до JWKS замість того, щоб робити кожен своє
+	reloadMu sync.Mutex
+	// Час останньої спроби, навіть невдалої, щоб недоступний JWKS не смикали на кожен запит
+	attemptedAt time.Time
+	stop        chan struct{}
+}
+
+func newKeyStore(load keysLoader, refreshInterval time.Duration) (*keyStore, error) {
+	s := &keyStore{
+		load: load,
+		stop: make(chan struct{}),
+	}
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	if refreshInterval > 0 {
+		go s.refreshLoop(refreshInterval)
+	}
+
+	return s, nil
+}
+
+func (s *keyStore) reload() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	return s.reloadLocked()
+}
+
+// reloadIfStale перезавантажує ключі, лише якщо остання спроба була
+// давніше за minKeysReloadInterval. Хто чекав на reloadMu, поки інший
+// запит оновлював ключі, бачить свіжу спробу і не повторює її.
+func (s *keyStore) reloadIfStale() {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	if time.Since(s.attemptedAt) <= minKeysReloadInterval {
+		return
+	}
+
+	if err := s.reloadLocked(); err != nil {
+		slog.Warn("Не вдалося оновити ключі JWT", "err", err.Error())
+	}
+}
+
+// reloadLocked викликається під reloadMu
+func (s *keyStore) reloadLocked() error {
+	s.attemptedAt = time.Now()
+
+	keys, err := s.load()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no verification keys loaded")
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *keyStore) refreshLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.reload(); err != nil {
+				slog.Warn("Не вдалося оновити ключі JWT", "err", err.Error())
+			}
+		}
+	}
+}
+
+func (s *keyStore) key(kid string) (any, error) {
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+
+	// Невідомий kid — ймовірно, ключі щойно ротували
+	s.reloadIfStale()
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+func (s *keyStore) lookup(kid string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, true
+	}
+
+	// Єдиний ключ без kid підходить для будь-якого токена
+	if key, ok := s.keys[""]; ok && len(s.keys) == 1 {
+		return key, true
+	}
+
+	return nil, false
+}
+
+func (s *keyStore) close() {
+	close(s.stop)
+}
+
+func secretKeys(secret []byte) keysLoader {
+	return func() (map[string]any, error) {
+		return map[string]any{"": secret}, nil
+	}
+}
+
+// pemKeys читає з файлу один або кілька блоків PUBLIC KEY.
+// kid береться із заголовка блоку "Kid", інакше рахується RFC 7638 thumbprint.
+// Єдиний ключ без заголовка Kid приймається для токенів з будь-яким kid.
+func pemKeys(path string) keysLoader {
+	return func() (map[string]any, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read JWT public key: %w", err)
+		}
+
+		keys := make(map[string]any)
+		var withoutKid *rsa.PublicKey
+
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+
+			parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse JWT public key: %w", err)
+			}
+
+			publicKey, ok := parsed.(*rsa.PublicKey)
+			if !ok {
+				return nil, fmt.Errorf("JWT public key is not RSA")
+			}
+
+			kid := block.Headers["Kid"]
+			if kid == "" {
+				withoutKid = publicKey
+				kid = rsaThumbprint(publicKey)
+			}
+			keys[kid] = publicKey
+		}
+
+		if len(keys) == 1 && withoutKid != nil {
+			return map[string]any{"": withoutKid}, nil
+		}
+
+		return keys, nil
+	}
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func jwksKeys(client *http.Client, url string) keysLoader {
+	return func() (map[string]any, error) {
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("JWKS request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("JWKS returned status %d", resp.StatusCode)
+		}
+
+		var set struct {
+			Keys []jwk `json:"keys"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+			return nil, fmt.Errorf("cannot decode JWKS: %w", err)
+		}
+
+		keys := make(map[string]any, len(set.Keys))
+
+		for _, k := range set.Keys {
+			if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+				continue
+			}
+
+			publicKey, err := k.rsaPublicKey()
+			if err != nil {
+				slog.Warn("Пропущено некоректний ключ JWKS", "kid", k.Kid, "err", err.Error())
+				continue
+			}
+			keys[k.Kid] = publicKey
+		}
+
+		return keys, nil
+	}
+}
+
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("bad modulus: %w", err)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("bad exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("exponent is too large")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}, nil
+}
+
+func rsaThumbprint(key *rsa.PublicKey) string {
+	e := big.NewInt(int64(key.E)).Bytes()
+
+	// RFC 7638: обов'язкові поля у лексикографічному порядку без пробілів
+	canonical := fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+		base64.RawURLEncoding.EncodeToString(e),
+		base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+	)
+	sum := sha256.Sum256([]byte(canonical))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// fakeJWKS — сервер авторизації, що віддає поточний набір ключів і рахує запити
+type fakeJWKS struct {
+	mu       sync.Mutex
+	keys     map[string]*rsa.PrivateKey
+	failing  bool
+	requests atomic.Int32
+}
+
+func (f *fakeJWKS) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	f.requests.Add(1)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failing {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	for kid, key := range f.keys {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	_ = json.NewEncoder(w).Encode(set)
+}
+
+func (f *fakeJWKS) set(keys map[string]*rsa.PrivateKey, failing bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.keys = keys
+	f.failing = failing
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	return key
+}
+
+func signToken(t *testing.T, kid string, key *rsa.PrivateKey) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"user_id": 7,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	return signed
+}
+
+// expireReloadAttempt імітує, що з останньої спроби минув minKeysReloadInterval
+func expireReloadAttempt(a *Authenticator) {
+	a.keys.reloadMu.Lock()
+	a.keys.attemptedAt = time.Now().Add(-minKeysReloadInterval - time.Second)
+	a.keys.reloadMu.Unlock()
+}
+
+func TestJWKSKeyRotation(t *testing.T) {
+	oldKey, newKey, newerKey := newRSAKey(t), newRSAKey(t), newRSAKey(t)
+
+	jwks := &fakeJWKS{}
+	jwks.set(map[string]*rsa.PrivateKey{"old": oldKey}, false)
+	server := httptest.NewServer(jwks)
+	defer server.Close()
+
+	a, err := NewAuthenticator(Options{Algorithm: "RS256", JWKSURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	defer a.keys.close()
+
+	assertAccepted := func(kid string, key *rsa.PrivateKey) {
+		t.Helper()
+		if _, err := a.UserIDFromToken(signToken(t, kid, key)); err != nil {
+			t.Fatalf("token with kid %q rejected: %v", kid, err)
+		}
+	}
+	assertRejected := func(kid string, key *rsa.PrivateKey) {
+		t.Helper()
+		if _, err := a.UserIDFromToken(signToken(t, kid, key)); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("token with kid %q: err = %v, want ErrInvalidToken", kid, err)
+		}
+	}
+	assertRequests := func(want int32) {
+		t.Helper()
+		if got := jwks.requests.Load(); got != want {
+			t.Fatalf("JWKS requests = %d, want %d", got, want)
+		}
+	}
+
+	assertAccepted("old", oldKey)
+	assertRequests(1)
+
+	// Ключі ротували, але з останньої спроби ще не минув інтервал — JWKS не смикаємо
+	jwks.set(map[string]*rsa.PrivateKey{"old": oldKey, "new": newKey}, false)
+	assertRejected("new", newKey)
+	assertRequests(1)
+
+	// Невідомий kid після інтервалу перезавантажує ключі, старий kid лишається дійсним
+	expireReloadAttempt(a)
+	assertAccepted("new", newKey)
+	assertRequests(2)
+	assertAccepted("old", oldKey)
+	assertRequests(2)
+
+	// Вигаданий kid одразу після перезавантаження не викликає нового запиту
+	assertRejected("bogus", newKey)
+	assertRequests(2)
+
+	// Невдала спроба теж враховується в інтервалі
+	jwks.set(nil, true)
+	expireReloadAttempt(a)
+	assertRejected("bogus", newKey)
+	assertRequests(3)
+	assertRejected("bogus", newKey)
+	assertRequests(3)
+
+	// Після невдалої спроби попередні ключі продовжують працювати
+	assertAccepted("new", newKey)
+
+	// Одночасні запити з новим kid роблять лише одне звернення до JWKS
+	jwks.set(map[string]*rsa.PrivateKey{"new": newKey, "newer": newerKey}, false)
+	expireReloadAttempt(a)
+
+	token := signToken(t, "newer", newerKey)
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := a.UserIDFromToken(token); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("token with kid %q rejected: %v", "newer", err)
+	}
+	assertRequests(4)
+
+	// Ключ, прибраний з JWKS, більше не приймається
+	assertRejected("old", oldKey)
+}
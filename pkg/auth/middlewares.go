@@ -1,94 +1,97 @@
 package auth
 
 import (
-	"context"
-	"log/slog"
+	"fmt"
 	"net/http"
 	"strings"
 )
 
-func AuthMiddleware(next func(w http.ResponseWriter, r *http.Request)) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			slog.Debug("Заголовок авторизації відсутній")
-			http.Error(w, "Не авторизовано", http.StatusUnauthorized)
-			return
-		}
+const bearerRealm = "lots"
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-		userID, err := UserIDFromToken(tokenString)
+func (a *Authenticator) AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := a.principalFromRequest(r)
 		if err != nil {
-			slog.Debug("Помилка авторизації", "err", err.Error())
-			http.Error(w, "Не авторизовано", http.StatusUnauthorized)
+			a.fail(w, r, err)
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), "user_id", userID)
-		next(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), principal)))
 	})
 }
 
-func OptionalAuthMiddleware(next func(w http.ResponseWriter, r *http.Request)) http.Handler {
+// OptionalAuthMiddleware пропускає анонімні запити і запити з недійсним
+// токеном, кладучи Principal у контекст лише для коректного токена
+func (a *Authenticator) OptionalAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		userID := 0
-		authHeader := r.Header.Get("Authorization")
-		if authHeader != "" {
-			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-			if id, err := UserIDFromToken(tokenString); err != nil {
-				// slog.Debug("У OptionalAuthMiddleware нема авторизації", "err", err.Error())
-				userID = 0
-			} else {
-				userID = id
-			}
+		ctx := r.Context()
+
+		if principal, err := a.principalFromRequest(r); err == nil {
+			ctx = NewContext(ctx, principal)
 		}
 
-		ctx := context.WithValue(r.Context(), "user_id", userID)
-		next(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-func AuthMiddlewareHandler(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			slog.Debug("Заголовок авторизації відсутній")
-			http.Error(w, "Не авторизовано", http.StatusUnauthorized)
-			return
-		}
+func (a *Authenticator) principalFromRequest(r *http.Request) (*Principal, error) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return nil, ErrMissingToken
+	}
+
+	tokenString, ok := strings.CutPrefix(authHeader, "Bearer ")
+	if !ok || tokenString == "" {
+		return nil, ErrMalformedToken
+	}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	principal, err := a.PrincipalFromToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
 
-		userID, err := UserIDFromToken(tokenString)
+	if a.revocations != nil {
+		revoked, err := a.revocations.IsRevoked(r.Context(), principal)
 		if err != nil {
-			slog.Debug("Помилка авторизації", "err", err.Error())
-			http.Error(w, "Не авторизовано", http.StatusUnauthorized)
-			return
+			return nil, fmt.Errorf("%w: %v", ErrRevocationUnavailable, err)
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
 		}
+	}
 
-		ctx := context.WithValue(r.Context(), "user_id", userID)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+	return principal, nil
 }
 
-func OptionalAuthMiddlewareHandler(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		userID := 0
-		authHeader := r.Header.Get("Authorization")
-		if authHeader != "" {
-			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-			if id, err := UserIDFromToken(tokenString); err != nil {
-				// slog.Debug("У OptionalAuthMiddleware нема авторизації", "err", err.Error())
-				userID = 0
-			} else {
-				userID = id
-			}
-		}
+// fail ставить заголовок WWW-Authenticate за RFC 6750 і передає відмову в ErrorWriter
+func (a *Authenticator) fail(w http.ResponseWriter, r *http.Request, err error) {
+	authErr := NewError(err)
 
-		ctx := context.WithValue(r.Context(), "user_id", userID)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+	if challenge := authErr.Challenge(); challenge != "" {
+		w.Header().Set("WWW-Authenticate", challenge)
+	}
+	a.onError(w, r, authErr)
+}
+
+// Challenge — значення WWW-Authenticate для Bearer-відмови за RFC 6750.
+// Порожнє для відмов, не пов'язаних із токеном (API-ключ, недоступність).
+func (e *Error) Challenge() string {
+	challenge := fmt.Sprintf(`Bearer realm=%q`, bearerRealm)
+	switch e.Code {
+	case CodeMissingToken:
+	case CodeAuthUnavailable, CodeMissingAPIKey, CodeInvalidAPIKey:
+		return ""
+	case CodeForbidden:
+		challenge += `, error="insufficient_scope"`
+	case CodeTokenRevoked:
+		challenge += `, error="invalid_token", error_description="The access token has been revoked"`
+	case CodeTokenExpired:
+		challenge += `, error="invalid_token", error_description="The access token expired"`
+	case CodeMalformedToken:
+		challenge += `, error="invalid_token", error_description="The access token is malformed"`
+	default:
+		challenge += `, error="invalid_token", error_description="The access token is invalid"`
+	}
+
+	return challenge
 }
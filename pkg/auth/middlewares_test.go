@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var testSecret = []byte("test-secret")
+
+type revokedTokens map[string]bool
+
+func (r revokedTokens) IsRevoked(_ context.Context, principal *Principal) (bool, error) {
+	return r[principal.TokenID], nil
+}
+
+type failingRevocations struct{}
+
+func (failingRevocations) IsRevoked(context.Context, *Principal) (bool, error) {
+	return false, errors.New("db is down")
+}
+
+func hsToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	return signed
+}
+
+// rsaPublicKeyFile зберігає публічний ключ у PEM і повертає шлях та вміст файлу
+func rsaPublicKeyFile(t *testing.T) (string, []byte) {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(&newRSAKey(t).PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	path := filepath.Join(t.TempDir(), "jwt.pem")
+	if err := os.WriteFile(path, pemBytes, 0o600); err != nil {
+		t.Fatalf("write public key: %v", err)
+	}
+
+	return path, pemBytes
+}
+
+func TestAuthMiddlewareErrorCodes(t *testing.T) {
+	now := time.Now()
+	hour := now.Add(time.Hour).Unix()
+
+	valid := jwt.MapClaims{"user_id": 7, "jti": "ok", "exp": hour}
+	expired := jwt.MapClaims{"user_id": 7, "exp": now.Add(-time.Hour).Unix()}
+	revoked := jwt.MapClaims{"user_id": 7, "jti": "revoked", "exp": hour}
+
+	// Токен, підписаний HS256 публічним ключем RS256 як секретом (alg confusion)
+	publicKeyPath, publicKeyPEM := rsaPublicKeyFile(t)
+	confused, err := jwt.NewWithClaims(jwt.SigningMethodHS256, valid).SignedString(publicKeyPEM)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	rs256 := &Options{Algorithm: "RS256", PublicKeyPath: publicKeyPath}
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, valid).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	const skew = time.Minute
+	strict := &Options{Algorithm: "HS256", Secret: testSecret, Issuer: "auth-service", Audience: "lots-service", ClockSkew: skew}
+	scoped := func(claims jwt.MapClaims) jwt.MapClaims {
+		scopedClaims := jwt.MapClaims{"user_id": 7, "iss": "auth-service", "aud": "lots-service", "exp": hour}
+		for name, value := range claims {
+			scopedClaims[name] = value
+		}
+		return scopedClaims
+	}
+	insideSkew, outsideSkew := now.Add(-skew/2).Unix(), now.Add(-2*skew).Unix()
+	insideNbf, outsideNbf := now.Add(skew/2).Unix(), now.Add(2*skew).Unix()
+
+	tests := []struct {
+		name        string
+		opts        *Options
+		header      string
+		revocations RevocationChecker
+		roles       []string
+		wantStatus  int
+		wantCode    string
+	}{
+		{name: "HS256 token for RS256 key", opts: rs256, header: "Bearer " + confused, wantStatus: http.StatusUnauthorized, wantCode: CodeInvalidToken},
+		{name: "alg none", header: "Bearer " + unsigned, wantStatus: http.StatusUnauthorized, wantCode: CodeInvalidToken},
+		{name: "no exp", header: "Bearer " + hsToken(t, jwt.MapClaims{"user_id": 7}), wantStatus: http.StatusUnauthorized, wantCode: CodeInvalidToken},
+		{name: "issuer and audience", opts: strict, header: "Bearer " + hsToken(t, scoped(nil)), wantStatus: http.StatusOK},
+		{name: "wrong issuer", opts: strict, header: "Bearer " + hsToken(t, scoped(jwt.MapClaims{"iss": "other"})), wantStatus: http.StatusUnauthorized, wantCode: CodeInvalidToken},
+		{name: "wrong audience", opts: strict, header: "Bearer " + hsToken(t, scoped(jwt.MapClaims{"aud": "other"})), wantStatus: http.StatusUnauthorized, wantCode: CodeInvalidToken},
+		{name: "exp inside skew", opts: strict, header: "Bearer " + hsToken(t, scoped(jwt.MapClaims{"exp": insideSkew})), wantStatus: http.StatusOK},
+		{name: "exp outside skew", opts: strict, header: "Bearer " + hsToken(t, scoped(jwt.MapClaims{"exp": outsideSkew})), wantStatus: http.StatusUnauthorized, wantCode: CodeTokenExpired},
+		{name: "nbf inside skew", opts: strict, header: "Bearer " + hsToken(t, scoped(jwt.MapClaims{"nbf": insideNbf})), wantStatus: http.StatusOK},
+		{name: "nbf outside skew", opts: strict, header: "Bearer " + hsToken(t, scoped(jwt.MapClaims{"nbf": outsideNbf})), wantStatus: http.StatusUnauthorized, wantCode: CodeInvalidToken},
+		{name: "missing", wantStatus: http.StatusUnauthorized, wantCode: CodeMissingToken},
+		{name: "not bearer", header: "Basic abc", wantStatus: http.StatusUnauthorized, wantCode: CodeMalformedToken},
+		{name: "garbage", header: "Bearer abc", wantStatus: http.StatusUnauthorized, wantCode: CodeMalformedToken},
+		{name: "expired", header: "Bearer " + hsToken(t, expired), wantStatus: http.StatusUnauthorized, wantCode: CodeTokenExpired},
+		{name: "revoked", header: "Bearer " + hsToken(t, revoked), revocations: revokedTokens{"revoked": true}, wantStatus: http.StatusUnauthorized, wantCode: CodeTokenRevoked},
+		{name: "revocations down", header: "Bearer " + hsToken(t, valid), revocations: failingRevocations{}, wantStatus: http.StatusServiceUnavailable, wantCode: CodeAuthUnavailable},
+		{name: "no role", header: "Bearer " + hsToken(t, valid), roles: []string{RoleAdmin}, wantStatus: http.StatusForbidden, wantCode: CodeForbidden},
+		{name: "ok", header: "Bearer " + hsToken(t, valid), wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Algorithm: "HS256", Secret: testSecret}
+			if tt.opts != nil {
+				opts = *tt.opts
+			}
+			opts.Revocations = tt.revocations
+
+			a, err := NewAuthenticator(opts)
+			if err != nil {
+				t.Fatalf("NewAuthenticator: %v", err)
+			}
+
+			var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {})
+			if tt.roles != nil {
+				handler = a.RequireRole(tt.roles...)(handler)
+			}
+			handler = a.AuthMiddleware(handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+
+			var resp errorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if resp.ErrorCode != tt.wantCode {
+				t.Fatalf("error_code = %q, want %q", resp.ErrorCode, tt.wantCode)
+			}
+
+			challenge := rec.Header().Get("WWW-Authenticate")
+			if tt.wantStatus == http.StatusServiceUnavailable {
+				if challenge != "" {
+					t.Fatalf("WWW-Authenticate = %q, want none", challenge)
+				}
+				return
+			}
+			if !strings.HasPrefix(challenge, "Bearer ") {
+				t.Fatalf("WWW-Authenticate = %q, want Bearer challenge", challenge)
+			}
+		})
+	}
+}
+
+func TestErrorWriterIsInjected(t *testing.T) {
+	var got *Error
+	a, err := NewAuthenticator(Options{
+		Algorithm: "HS256",
+		Secret:    testSecret,
+		ErrorWriter: func(w http.ResponseWriter, _ *http.Request, err *Error) {
+			got = err
+			w.WriteHeader(err.Status)
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	a.AuthMiddleware(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got == nil || got.Code != CodeMissingToken || !errors.Is(got, ErrMissingToken) {
+		t.Fatalf("ErrorWriter got %v, want missing_token", got)
+	}
+}
+
+func TestAPIKeyErrorCodes(t *testing.T) {
+	a, err := NewAPIKeyAuthenticator([]ServiceKey{{Name: "payments", Key: "secret", Scopes: []string{ScopeLotsRead}}}, nil)
+	if err != nil {
+		t.Fatalf("NewAPIKeyAuthenticator: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		scope      string
+		wantStatus int
+		wantCode   string
+	}{
+		{"missing", "", ScopeLotsRead, http.StatusUnauthorized, CodeMissingAPIKey},
+		{"unknown", "other", ScopeLotsRead, http.StatusUnauthorized, CodeInvalidAPIKey},
+		{"no scope", "secret", ScopeLotsMarkSold, http.StatusForbidden, CodeForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+
+			a.RequireScope(tt.scope)(http.NotFoundHandler()).ServeHTTP(rec, req)
+
+			var resp errorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if rec.Code != tt.wantStatus || resp.ErrorCode != tt.wantCode {
+				t.Fatalf("got %d %q, want %d %q", rec.Code, resp.ErrorCode, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+)
+
+// Principal — автентифікований користувач запиту
+type Principal struct {
+	UserID    int
+	Roles     []string
+	TokenID   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+func (p *Principal) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if slices.Contains(p.Roles, role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type principalKey struct{}
+
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext повертає користувача, якого поклав AuthMiddleware або
+// OptionalAuthMiddleware. false — анонімний запит або middleware відсутній.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// UserIDFromContext повертає 0 і false для анонімного запиту
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	principal, ok := FromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+
+	return principal.UserID, true
+}
+
+// RequireRole пропускає запит, якщо автентифікований користувач має хоча б
+// одну з ролей. Має стояти після AuthMiddleware.
+func (a *Authenticator) RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := FromContext(r.Context())
+			if !ok {
+				a.fail(w, r, ErrMissingToken)
+				return
+			}
+
+			if !principal.HasRole(roles...) {
+				a.fail(w, r, ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const ScopeAuthRevoke = "auth:revoke"
+
+// RevocationChecker перевіряє, чи не відкликано токен окремо (за jti)
+// або всі токени користувача, видані до моменту відкликання (бан, вихід з усіх пристроїв).
+// iat має точність до секунди, тому порівнюється з моментом відкликання, обрізаним
+// до секунди: токен, виданий у ту саму секунду (повторний вхід), лишається дійсним.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, principal *Principal) (bool, error)
+}
+
+type Revoker interface {
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	RevokeUser(ctx context.Context, userID int, revokedAt time.Time) error
+}
+
+type RevocationStore interface {
+	RevocationChecker
+	Revoker
+}
+
+// MemoryRevocations — denylist у пам'яті процесу. Записи токенів живуть до
+// їх exp, записи користувачів — userTTL (має бути не менше за час життя токена).
+type MemoryRevocations struct {
+	mu      sync.RWMutex
+	tokens  map[string]time.Time
+	users   map[int]time.Time
+	userTTL time.Duration
+}
+
+func NewMemoryRevocations(userTTL time.Duration) *MemoryRevocations {
+	return &MemoryRevocations{
+		tokens:  make(map[string]time.Time),
+		users:   make(map[int]time.Time),
+		userTTL: userTTL,
+	}
+}
+
+func (m *MemoryRevocations) IsRevoked(_ context.Context, principal *Principal) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if principal.TokenID != "" {
+		if _, ok := m.tokens[principal.TokenID]; ok {
+			return true, nil
+		}
+	}
+
+	if revokedAt, ok := m.users[principal.UserID]; ok {
+		return principal.IssuedAt.IsZero() || principal.IssuedAt.Before(revokedAt.Truncate(time.Second)), nil
+	}
+
+	return false, nil
+}
+
+func (m *MemoryRevocations) RevokeToken(_ context.Context, tokenID string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[tokenID] = expiresAt
+	m.purgeLocked(time.Now())
+
+	return nil
+}
+
+func (m *MemoryRevocations) RevokeUser(_ context.Context, userID int, revokedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.users[userID] = revokedAt
+	m.purgeLocked(time.Now())
+
+	return nil
+}
+
+func (m *MemoryRevocations) purgeLocked(now time.Time) {
+	for tokenID, expiresAt := range m.tokens {
+		if now.After(expiresAt) {
+			delete(m.tokens, tokenID)
+		}
+	}
+
+	for userID, revokedAt := range m.users {
+		if now.After(revokedAt.Add(m.userTTL)) {
+			delete(m.users, userID)
+		}
+	}
+}
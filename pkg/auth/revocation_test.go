@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryRevocationsUserIssuedAt(t *testing.T) {
+	revokedAt := time.Now().Truncate(time.Second).Add(500 * time.Millisecond)
+
+	m := NewMemoryRevocations(time.Hour)
+	if err := m.RevokeUser(context.Background(), 7, revokedAt); err != nil {
+		t.Fatalf("RevokeUser: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		issuedAt time.Time
+		want     bool
+	}{
+		{"issued a second before", revokedAt.Add(-time.Second).Truncate(time.Second), true},
+		{"issued in the same second", revokedAt.Truncate(time.Second), false},
+		{"issued after", revokedAt.Add(time.Second).Truncate(time.Second), false},
+		{"without iat", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked, err := m.IsRevoked(context.Background(), &Principal{UserID: 7, IssuedAt: tt.issuedAt})
+			if err != nil {
+				t.Fatalf("IsRevoked: %v", err)
+			}
+			if revoked != tt.want {
+				t.Fatalf("revoked = %v, want %v", revoked, tt.want)
+			}
+		})
+	}
+}
@@ -1,42 +1,123 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
-	"os"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
 )
 
-func NewPostgresConnection(host, dbName, user, password string) *sql.DB {
-	db, err := sql.Open("postgres", fmt.Sprintf("host=%s user=%s password=%s dbname=%s sslmode=disable", host, user, password, dbName))
+type Options struct {
+	// Готовий DSN має пріоритет над окремими полями
+	DSN      string
+	Host     string
+	Port     string
+	DBName   string
+	User     string
+	Password string
+	SSLMode  string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	Retry RetryPolicy
+}
+
+// RetryPolicy — очікування БД під час старту з експоненційною затримкою
+type RetryPolicy struct {
+	Attempts       int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// NewPostgresConnection відкриває пул з'єднань і чекає доступності БД згідно з opts.Retry.
+// Повертає помилку, якщо БД не стала доступною або ctx скасовано.
+func NewPostgresConnection(ctx context.Context, opts Options) (*sql.DB, error) {
+	db, err := sql.Open("postgres", opts.dsn())
 	if err != nil {
-		slog.Error("Problem with db connection:", "Error", err)
+		return nil, fmt.Errorf("open postgres: %w", err)
+	}
+
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+	db.SetMaxIdleConns(opts.MaxIdleConns)
+	db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+
+	if err := ping(ctx, db, opts.Retry); err != nil {
 		db.Close()
-		return nil
+		return nil, err
 	}
 
-	pingCounts := 0
-	for {
-		err := db.Ping()
-		if err == nil {
-			slog.Info("Successfully connected to Postgres")
+	slog.Info("Successfully connected to Postgres")
+
+	return db, nil
+}
+
+func ping(ctx context.Context, db *sql.DB, retry RetryPolicy) error {
+	attempts := max(retry.Attempts, 1)
+	backoff := retry.InitialBackoff
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = db.PingContext(ctx); err == nil {
+			return nil
+		}
+
+		if attempt == attempts {
 			break
 		}
 
-		slog.Warn("Postgres not ready...", "count", pingCounts, "err", err)
-		pingCounts++
+		slog.Warn("Postgres not ready...", "attempt", attempt, "attempts", attempts, "retryIn", backoff, "err", err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("connect to postgres: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
 
-		if pingCounts > 5 {
-			slog.Error("Could not connect to DB after many retries")
-			os.Exit(1)
+		backoff *= 2
+		if retry.MaxBackoff > 0 && backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
 		}
+	}
+
+	return fmt.Errorf("connect to postgres after %d attempts: %w", attempts, err)
+}
+
+func (o Options) dsn() string {
+	if o.DSN != "" {
+		return o.DSN
+	}
+
+	sslMode := o.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
 
-		slog.Info("Backing off for 2 seconds...")
-		time.Sleep(2 * time.Second)
+	params := []string{
+		"host=" + quoteDSNValue(o.Host),
+		"user=" + quoteDSNValue(o.User),
+		"password=" + quoteDSNValue(o.Password),
+		"dbname=" + quoteDSNValue(o.DBName),
+		"sslmode=" + quoteDSNValue(sslMode),
+	}
+	if o.Port != "" {
+		params = append(params, "port="+quoteDSNValue(o.Port))
 	}
 
-	return db
+	return strings.Join(params, " ")
+}
+
+// quoteDSNValue екранує значення для формату key=value libpq
+func quoteDSNValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+
+	return "'" + value + "'"
 }
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+)
+
+// ReportPoolStats періодично пише стан пулу в лог до скасування ctx
+func ReportPoolStats(ctx context.Context, db *sql.DB, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := db.Stats()
+			slog.Info("Postgres pool stats",
+				"maxOpen", stats.MaxOpenConnections,
+				"open", stats.OpenConnections,
+				"inUse", stats.InUse,
+				"idle", stats.Idle,
+				"waitCount", stats.WaitCount,
+				"waitDuration", stats.WaitDuration,
+				"maxIdleClosed", stats.MaxIdleClosed,
+				"maxIdleTimeClosed", stats.MaxIdleTimeClosed,
+				"maxLifetimeClosed", stats.MaxLifetimeClosed,
+			)
+		}
+	}
+}